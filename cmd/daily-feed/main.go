@@ -10,10 +10,15 @@ import (
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"github.com/ryosukesatoh/daily-feed/internal/archive"
 	"github.com/ryosukesatoh/daily-feed/internal/config"
 	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/fulltext"
+	"github.com/ryosukesatoh/daily-feed/internal/i18n"
 	"github.com/ryosukesatoh/daily-feed/internal/publisher"
+	"github.com/ryosukesatoh/daily-feed/internal/runlog"
 	"github.com/ryosukesatoh/daily-feed/internal/runner"
+	"github.com/ryosukesatoh/daily-feed/internal/state"
 	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
 )
 
@@ -27,75 +32,72 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Build fetcher
-	var f fetcher.Fetcher
-	switch cfg.Fetcher.Type {
-	case "arxiv":
-		f = fetcher.NewArxivFetcher()
-	default:
-		log.Fatalf("Unknown fetcher type: %s", cfg.Fetcher.Type)
+	if cfg.LocalesDir != "" {
+		if err := i18n.LoadDir(cfg.LocalesDir); err != nil {
+			log.Fatalf("Failed to load message catalogs: %v", err)
+		}
 	}
 
+	// Build fetcher
+	f := buildFetcher(cfg.Fetcher, cfg.GetTopicSpecs())
+
 	// Build summarizer
+	var cache *summarizer.Cache
+	if cfg.SummaryCache.Enabled {
+		cache, err = summarizer.NewCache(cfg.SummaryCache.Dir)
+		if err != nil {
+			log.Fatalf("Failed to set up summary cache: %v", err)
+		}
+		if cfg.FullText.Enabled {
+			cache.SetFullText(cfg.FullText.MaxChars)
+		}
+	}
+	var prompts *summarizer.Prompts
+	if cfg.Prompts.Dir != "" {
+		prompts, err = summarizer.LoadPrompts(cfg.Prompts.Dir, cfg.Prompts.Version)
+		if err != nil {
+			log.Fatalf("Failed to load prompt templates: %v", err)
+		}
+		log.Printf("Using prompt templates from %s (prompt version %s)", cfg.Prompts.Dir, prompts.Version())
+	}
 	var s summarizer.Summarizer
 	topics := cfg.GetTopics()
-	switch cfg.Summarizer.Type {
-	case "anthropic":
-		if len(topics) > 1 {
-			s = summarizer.NewAnthropicSummarizerMultiTopic(
-				cfg.Summarizer.APIKey,
-				cfg.Summarizer.Model,
-				cfg.Summarizer.MaxTokens,
-				cfg.TopN,
-				topics,
-				cfg.Language,
-			)
-		} else {
-			// Use legacy constructor for backward compatibility
-			var topic string
-			if len(topics) > 0 {
-				topic = topics[0]
-			}
-			s = summarizer.NewAnthropicSummarizer(
-				cfg.Summarizer.APIKey,
-				cfg.Summarizer.Model,
-				cfg.Summarizer.MaxTokens,
-				cfg.TopN,
-				topic,
-				cfg.Language,
-			)
+	if scs := cfg.GetSummarizers(); len(scs) > 1 {
+		chain := make([]summarizer.Summarizer, len(scs))
+		for i, sc := range scs {
+			chain[i] = buildSummarizer(sc, cfg, cache, prompts)
 		}
-	default:
-		log.Fatalf("Unknown summarizer type: %s", cfg.Summarizer.Type)
+		s = summarizer.NewChain(chain...)
+	} else {
+		s = buildSummarizer(scs[0], cfg, cache, prompts)
 	}
 
 	// Build publishers
 	var pubs []publisher.Publisher
-	var webPub *publisher.WebPublisher
+	var webPubs []*publisher.WebPublisher
 
-	switch cfg.Publisher.Type {
-	case "stdout":
-		pubs = append(pubs, publisher.NewStdoutPublisher())
-	case "email":
-		pubs = append(pubs, publisher.NewEmailPublisher(
-			cfg.Publisher.Email.SMTPHost,
-			cfg.Publisher.Email.SMTPPort,
-			cfg.Publisher.Email.Username,
-			cfg.Publisher.Email.Password,
-			cfg.Publisher.Email.From,
-			cfg.Publisher.Email.To,
-		))
-	case "web":
-		webPub = publisher.NewWebPublisher(cfg.Publisher.Web.Addr)
-		pubs = append(pubs, webPub)
-	case "discord":
-		pubs = append(pubs, publisher.NewDiscordPublisher(cfg.Publisher.Discord.WebhookURL))
-	default:
-		log.Fatalf("Unknown publisher type: %s", cfg.Publisher.Type)
+	for _, pc := range cfg.GetPublishers() {
+		pub := buildPublisher(pc)
+		if webPub, ok := pub.(*publisher.WebPublisher); ok {
+			webPubs = append(webPubs, webPub)
+		}
+		pubs = append(pubs, pub)
+	}
+
+	// Open the run log if configured
+	var runLog *runlog.Log
+	if cfg.RunLog != "" {
+		runLog, err = runlog.Open(cfg.RunLog)
+		if err != nil {
+			log.Fatalf("Failed to open run log: %v", err)
+		}
 	}
 
-	// Start web server if configured
-	if webPub != nil {
+	// Start web servers if configured
+	for _, webPub := range webPubs {
+		if runLog != nil {
+			webPub.SetRunLog(runLog)
+		}
 		if err := webPub.Start(); err != nil {
 			log.Fatalf("Failed to start web publisher: %v", err)
 		}
@@ -114,6 +116,46 @@ func main() {
 		r = runner.New(topic, cfg.MaxResults, f, s, pubs)
 	}
 
+	if runLog != nil {
+		r.SetRunLog(runLog)
+	}
+
+	// Account for token usage and cap the monthly cost if configured
+	prices := make(map[string]summarizer.Price, len(cfg.Cost.Prices))
+	for model, p := range cfg.Cost.Prices {
+		prices[model] = summarizer.Price{Input: p.Input, Output: p.Output}
+	}
+	r.SetPrices(prices, cfg.Cost.ShowInDigest)
+	if cfg.Cost.MonthlyBudget > 0 {
+		var fallback summarizer.Summarizer
+		if cfg.Cost.OverBudget != nil {
+			fallback = buildSummarizer(*cfg.Cost.OverBudget, cfg, cache, prompts)
+		}
+		r.SetBudget(cfg.Cost.MonthlyBudget, fallback)
+		log.Printf("Using a monthly budget of $%.2f", cfg.Cost.MonthlyBudget)
+	}
+
+	// Add full-text excerpts to the prompt if configured
+	if cfg.FullText.Enabled {
+		e, err := fulltext.New(cfg.FullText.CacheDir, cfg.FullText.MaxDownloads, cfg.FullText.MaxChars)
+		if err != nil {
+			log.Fatalf("Failed to set up full-text stage: %v", err)
+		}
+		r.SetEnricher(e)
+	}
+
+	// Attach the seen-paper store if configured
+	if cfg.State.Type == "file" {
+		store, err := state.NewFileStore(cfg.State.Path)
+		if err != nil {
+			log.Fatalf("Failed to open state store: %v", err)
+		}
+		defer store.Close()
+		r.SetStore(store, time.Duration(*cfg.State.RetentionDays)*24*time.Hour)
+		r.SetIncremental(cfg.Fetcher.Incremental)
+		log.Printf("Using state store %s (retention %d days)", cfg.State.Path, *cfg.State.RetentionDays)
+	}
+
 	// Single-run mode: run the pipeline once and exit
 	if *once {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -162,13 +204,180 @@ func main() {
 	cancel()
 	c.Stop()
 
-	if webPub != nil {
+	if len(webPubs) > 0 {
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer shutdownCancel()
-		if err := webPub.Shutdown(shutdownCtx); err != nil {
-			log.Printf("Web server shutdown error: %v", err)
+		for _, webPub := range webPubs {
+			if err := webPub.Shutdown(shutdownCtx); err != nil {
+				log.Printf("Web server shutdown error: %v", err)
+			}
 		}
 	}
 
 	log.Println("Shutdown complete")
-}
\ No newline at end of file
+}
+
+// buildFetcher creates the fetcher described by the fetcher config. Structured
+// topics are compiled into arXiv queries.
+func buildFetcher(fc config.FetcherConfig, topics []config.TopicConfig) fetcher.Fetcher {
+	switch fc.Type {
+	case "arxiv":
+		f := fetcher.NewArxivFetcher()
+		f.SetQueries(arxivQueries(topics))
+		f.SetPaging(fc.Arxiv.PageSize, fc.Arxiv.MaxTotal)
+		f.SetMinPerTopic(fc.Arxiv.MinPerTopic)
+		return f
+	case "semanticscholar":
+		return fetcher.NewSemanticScholarFetcher(
+			fc.SemanticScholar.APIKey,
+			fc.SemanticScholar.Days,
+			fc.SemanticScholar.Venues,
+		)
+	case "pubmed":
+		return fetcher.NewPubMedFetcher(fc.PubMed.APIKey, fc.PubMed.Email, fc.PubMed.Days)
+	case "biorxiv", "medrxiv":
+		return fetcher.NewBiorxivFetcher(fc.Type, fc.Biorxiv.Days)
+	case "feed":
+		return fetcher.NewFeedFetcher(fc.Feed.URLs, fc.Feed.Days)
+	case "openreview":
+		return fetcher.NewOpenReviewFetcher(fc.OpenReview.Venue, fc.OpenReview.Invitation)
+	default:
+		log.Fatalf("Unknown fetcher type: %s", fc.Type)
+		return nil
+	}
+}
+
+// buildSummarizer creates the summarizer described by a single summarizer
+// config entry, wrapping the backend for map_reduce mode. A non-nil cache and
+// prompt templates are shared by every summarizer that supports them.
+func buildSummarizer(sc config.SummarizerConfig, cfg *config.Config, cache *summarizer.Cache, prompts *summarizer.Prompts) summarizer.Summarizer {
+	s := buildSummarizerBackend(sc, cfg)
+	if sc.Mode == "map_reduce" {
+		c, ok := s.(summarizer.Completer)
+		if !ok {
+			log.Fatalf("Summarizer type %s does not support map_reduce mode", sc.Type)
+		}
+		mr := summarizer.NewMapReduceSummarizer(c, cfg.TopN, cfg.GetTopics(), cfg.Language)
+		mr.SetBatching(sc.MapReduce.BatchSize, sc.MapReduce.Concurrency)
+		mr.SetShortlist(sc.MapReduce.Shortlist)
+		mr.SetTokenBudgets(sc.MapReduce.MapMaxTokens, sc.MapReduce.ReduceMaxTokens)
+		s = mr
+	}
+	if cs, ok := s.(interface{ SetCache(*summarizer.Cache) }); ok && cache != nil {
+		cs.SetCache(cache)
+	}
+	if ps, ok := s.(interface{ SetPrompts(*summarizer.Prompts) }); ok && prompts != nil {
+		ps.SetPrompts(prompts)
+	}
+	return s
+}
+
+// buildSummarizerBackend creates the summarizer of the configured type.
+func buildSummarizerBackend(sc config.SummarizerConfig, cfg *config.Config) summarizer.Summarizer {
+	topics := cfg.GetTopics()
+	switch sc.Type {
+	case "anthropic":
+		if len(topics) > 1 {
+			return summarizer.NewAnthropicSummarizerMultiTopic(
+				sc.APIKey,
+				sc.Model,
+				sc.MaxTokens,
+				cfg.TopN,
+				topics,
+				cfg.Language,
+			)
+		}
+		// Use legacy constructor for backward compatibility
+		var topic string
+		if len(topics) > 0 {
+			topic = topics[0]
+		}
+		return summarizer.NewAnthropicSummarizer(
+			sc.APIKey,
+			sc.Model,
+			sc.MaxTokens,
+			cfg.TopN,
+			topic,
+			cfg.Language,
+		)
+	case "openai":
+		oa := summarizer.NewOpenAISummarizer(
+			sc.BaseURL,
+			sc.APIKey,
+			sc.Model,
+			sc.MaxTokens,
+			cfg.TopN,
+			topics,
+			cfg.Language,
+		)
+		oa.SetHeaders(sc.Headers)
+		oa.SetJSONMode(sc.JSONMode)
+		return oa
+	case "ollama":
+		ol := summarizer.NewOllamaSummarizer(sc.BaseURL, sc.Model, cfg.TopN, topics, cfg.Language)
+		ol.SetEndpoint(sc.API)
+		temperature := -1.0
+		if sc.Temperature != nil {
+			temperature = *sc.Temperature
+		}
+		ol.SetOptions(sc.NumCtx, temperature)
+		return ol
+	case "extractive":
+		return summarizer.NewExtractiveSummarizer(cfg.TopN, topics, cfg.Language)
+	default:
+		log.Fatalf("Unknown summarizer type: %s", sc.Type)
+		return nil
+	}
+}
+
+// buildPublisher creates the publisher described by a single publisher config entry.
+func buildPublisher(pc config.PublisherConfig) publisher.Publisher {
+	switch pc.Type {
+	case "stdout":
+		return publisher.NewStdoutPublisher()
+	case "email":
+		return publisher.NewEmailPublisher(
+			pc.Email.SMTPHost,
+			pc.Email.SMTPPort,
+			pc.Email.Username,
+			pc.Email.Password,
+			pc.Email.From,
+			pc.Email.To,
+		)
+	case "web":
+		a, err := archive.Open(pc.Web.ArchiveDir)
+		if err != nil {
+			log.Fatalf("Failed to open digest archive: %v", err)
+		}
+		wp := publisher.NewWebPublisher(pc.Web.Addr, a)
+		wp.SetFeed(pc.Web.BaseURL, pc.Web.Feed.Mode, pc.Web.Feed.Limit)
+		return wp
+	case "discord":
+		return publisher.NewDiscordPublisher(pc.Discord.WebhookURL)
+	default:
+		log.Fatalf("Unknown publisher type: %s", pc.Type)
+		return nil
+	}
+}
+
+// arxivQueries maps the structured topics to arXiv queries by topic name.
+// Dates were validated when the config was loaded.
+func arxivQueries(topics []config.TopicConfig) map[string]fetcher.ArxivQuery {
+	queries := make(map[string]fetcher.ArxivQuery)
+	for _, t := range topics {
+		if !t.IsStructured() {
+			continue
+		}
+		from, to, _ := t.Submitted.Bounds()
+		queries[t.Name] = fetcher.ArxivQuery{
+			All:           t.All,
+			Any:           t.Any,
+			Not:           t.Not,
+			Topic:         t.Name,
+			SubmittedFrom: from,
+			SubmittedTo:   to,
+			Raw:           t.Query,
+		}
+	}
+	return queries
+}
@@ -0,0 +1,135 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
+)
+
+// DateLayout is the layout used for archive keys, file names and URLs.
+const DateLayout = "2006-01-02"
+
+// Archive keeps every published digest, one per day. When a directory is
+// configured each digest is also written there as <date>.json so the history
+// survives restarts. A later digest on the same day replaces the earlier one.
+type Archive struct {
+	dir     string
+	mu      sync.RWMutex
+	digests map[string]*summarizer.Digest
+}
+
+// Open loads the archive from dir. An empty dir keeps the archive in memory only.
+func Open(dir string) (*Archive, error) {
+	a := &Archive{
+		dir:     dir,
+		digests: make(map[string]*summarizer.Digest),
+	}
+	if dir == "" {
+		return a, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("archive: failed to create %s: %w", dir, err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("archive: failed to list %s: %w", dir, err)
+	}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("archive: failed to read %s: %w", file, err)
+		}
+		var d summarizer.Digest
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return nil, fmt.Errorf("archive: failed to parse %s: %w", file, err)
+		}
+		a.digests[strings.TrimSuffix(filepath.Base(file), ".json")] = &d
+	}
+
+	return a, nil
+}
+
+// Add stores a digest under its date and persists it if a directory is
+// configured. The archive keeps one digest per day: a digest dated on a day
+// that already has one replaces it, in memory and on disk.
+func (a *Archive) Add(d *summarizer.Digest) error {
+	date := d.Date.Format(DateLayout)
+
+	if a.dir != "" {
+		raw, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return fmt.Errorf("archive: failed to encode digest: %w", err)
+		}
+		path := filepath.Join(a.dir, date+".json")
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+			return fmt.Errorf("archive: failed to write %s: %w", tmp, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return fmt.Errorf("archive: failed to replace %s: %w", path, err)
+		}
+	}
+
+	a.mu.Lock()
+	a.digests[date] = d
+	a.mu.Unlock()
+	return nil
+}
+
+// Get returns the digest published on date (formatted as DateLayout).
+func (a *Archive) Get(date string) (*summarizer.Digest, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	d, ok := a.digests[date]
+	return d, ok
+}
+
+// Latest returns the most recent digest, or nil if the archive is empty.
+func (a *Archive) Latest() *summarizer.Digest {
+	dates := a.Dates()
+	if len(dates) == 0 {
+		return nil
+	}
+	d, _ := a.Get(dates[0])
+	return d
+}
+
+// Dates returns the dates of all archived digests, newest first.
+func (a *Archive) Dates() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	dates := make([]string, 0, len(a.digests))
+	for date := range a.digests {
+		dates = append(dates, date)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+	return dates
+}
+
+// Neighbors returns the dates of the digests immediately before and after
+// date. Either is empty when there is no such digest.
+func (a *Archive) Neighbors(date string) (prev, next string) {
+	dates := a.Dates()
+	for i, d := range dates {
+		if d != date {
+			continue
+		}
+		if i+1 < len(dates) {
+			prev = dates[i+1]
+		}
+		if i > 0 {
+			next = dates[i-1]
+		}
+		break
+	}
+	return prev, next
+}
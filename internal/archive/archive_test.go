@@ -0,0 +1,112 @@
+package archive
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
+)
+
+func digestOn(day int, overview string) *summarizer.Digest {
+	return &summarizer.Digest{
+		Topic:    "machine learning",
+		Topics:   []string{"machine learning"},
+		Date:     time.Date(2025, 1, day, 8, 0, 0, 0, time.UTC),
+		Overview: overview,
+		Summaries: []summarizer.PaperSummary{
+			{
+				Paper:     fetcher.Paper{ID: "2501.00001", Title: "Paper", URL: "http://arxiv.org/abs/2501.00001"},
+				Summary:   "Summary.",
+				KeyPoints: []string{"Point"},
+			},
+		},
+	}
+}
+
+func TestArchivePersistsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+
+	a, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	for _, d := range []*summarizer.Digest{digestOn(14, "Day 14"), digestOn(15, "Day 15"), digestOn(16, "Day 16")} {
+		if err := a.Add(d); err != nil {
+			t.Fatalf("Add returned error: %v", err)
+		}
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Reopen returned error: %v", err)
+	}
+
+	dates := reopened.Dates()
+	if len(dates) != 3 || dates[0] != "2025-01-16" || dates[2] != "2025-01-14" {
+		t.Fatalf("Expected 3 dates newest first, got %v", dates)
+	}
+	if latest := reopened.Latest(); latest == nil || latest.Overview != "Day 16" {
+		t.Errorf("Expected latest digest 'Day 16', got %+v", latest)
+	}
+
+	d, ok := reopened.Get("2025-01-15")
+	if !ok {
+		t.Fatal("Expected digest for 2025-01-15")
+	}
+	if len(d.Summaries) != 1 || d.Summaries[0].Paper.ID != "2501.00001" {
+		t.Errorf("Expected summaries to round-trip, got %+v", d.Summaries)
+	}
+
+	prev, next := reopened.Neighbors("2025-01-15")
+	if prev != "2025-01-14" || next != "2025-01-16" {
+		t.Errorf("Expected neighbors 2025-01-14/2025-01-16, got %q/%q", prev, next)
+	}
+	prev, next = reopened.Neighbors("2025-01-16")
+	if prev != "2025-01-15" || next != "" {
+		t.Errorf("Expected neighbors 2025-01-15/'', got %q/%q", prev, next)
+	}
+}
+
+func TestArchiveSameDayReplaces(t *testing.T) {
+	dir := t.TempDir()
+	a, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	morning := digestOn(15, "Morning")
+	evening := digestOn(15, "Evening")
+	evening.Date = evening.Date.Add(10 * time.Hour)
+	for _, d := range []*summarizer.Digest{morning, evening} {
+		if err := a.Add(d); err != nil {
+			t.Fatalf("Add returned error: %v", err)
+		}
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Reopen returned error: %v", err)
+	}
+	for name, archive := range map[string]*Archive{"in memory": a, "reopened": reopened} {
+		if dates := archive.Dates(); len(dates) != 1 || dates[0] != "2025-01-15" {
+			t.Fatalf("%s: expected a single date, got %v", name, dates)
+		}
+		if d, _ := archive.Get("2025-01-15"); d.Overview != "Evening" {
+			t.Errorf("%s: expected the later digest to replace the earlier one, got %q", name, d.Overview)
+		}
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 1 {
+		t.Errorf("Expected one file for the day, got %v", files)
+	}
+}
+
+func TestArchiveEmpty(t *testing.T) {
+	a, err := Open("")
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if a.Latest() != nil {
+		t.Error("Expected nil latest digest for empty archive")
+	}
+}
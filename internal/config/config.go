@@ -3,34 +3,210 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/ryosukesatoh/daily-feed/internal/i18n"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Topic      string           `yaml:"topic"`      // Legacy single topic support
-	Topics     []string         `yaml:"topics"`     // New multiple topics support
-	Language   string           `yaml:"language"`
-	Schedule   string           `yaml:"schedule"`
-	MaxResults int              `yaml:"max_results"`
-	TopN       int              `yaml:"top_n"`
-	RunOnStart bool             `yaml:"run_on_start"`
-	Fetcher    FetcherConfig    `yaml:"fetcher"`
-	Summarizer SummarizerConfig `yaml:"summarizer"`
-	Publisher  PublisherConfig  `yaml:"publisher"`
+	Topic        string             `yaml:"topic"`  // Legacy single topic support
+	Topics       []TopicConfig      `yaml:"topics"` // New multiple topics support
+	Language     string             `yaml:"language"`
+	Schedule     string             `yaml:"schedule"`
+	MaxResults   int                `yaml:"max_results"`
+	TopN         int                `yaml:"top_n"`
+	RunOnStart   bool               `yaml:"run_on_start"`
+	Fetcher      FetcherConfig      `yaml:"fetcher"`
+	Summarizer   SummarizerConfig   `yaml:"summarizer"`  // Legacy single summarizer support
+	Summarizers  []SummarizerConfig `yaml:"summarizers"` // Fallback chain, tried in order
+	Publisher    PublisherConfig    `yaml:"publisher"`   // Legacy single publisher support
+	Publishers   []PublisherConfig  `yaml:"publishers"`  // Multiple publishers support
+	State        StateConfig        `yaml:"state"`
+	FullText     FullTextConfig     `yaml:"full_text"`
+	SummaryCache SummaryCacheConfig `yaml:"summary_cache"`
+	Cost         CostConfig         `yaml:"cost"`
+	Prompts      PromptsConfig      `yaml:"prompts"`
+	RunLog       string             `yaml:"run_log"`     // JSON Lines file recording every run; empty disables
+	LocalesDir   string             `yaml:"locales_dir"` // Extra <language>.yaml message catalogs
 }
 
+// FullTextConfig controls the optional stage that adds excerpts of the full
+// text of arXiv papers to the summarizer prompt.
+type FullTextConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	CacheDir     string `yaml:"cache_dir"`     // Extracted excerpts, reused across runs
+	MaxDownloads int    `yaml:"max_downloads"` // Download budget per run
+	MaxChars     int    `yaml:"max_chars"`     // Bound on the excerpt of each paper
+}
+
+// SummaryCacheConfig controls the on-disk cache of per-paper summaries, which
+// lets papers seen in an earlier run skip the model.
+type SummaryCacheConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Dir     string `yaml:"dir"`
+}
+
+// PromptsConfig overrides the built-in prompt templates.
+type PromptsConfig struct {
+	Dir     string `yaml:"dir"`     // Holds <kind>.<language>.tmpl files
+	Version string `yaml:"version"` // Recorded with digests; derived from the templates when empty
+}
+
+// CostConfig controls token accounting and the monthly budget.
+type CostConfig struct {
+	Prices        map[string]PriceConfig `yaml:"prices"`         // By model name or backend, e.g. "openai/gpt-4o"
+	ShowInDigest  bool                   `yaml:"show_in_digest"` // Add the token usage to digest footers
+	MonthlyBudget float64                `yaml:"monthly_budget"` // USD; 0 means no cap
+	OverBudget    *SummarizerConfig      `yaml:"over_budget"`    // Cheaper summarizer once the budget is spent; unset skips runs
+}
+
+// PriceConfig is the price of a model in USD per million tokens.
+type PriceConfig struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// TopicConfig is a topic given either as a plain string, which searches all
+// fields for the text, or as a mapping with a display name and a structured
+// arXiv query. Terms may use the field prefixes ti:, abs:, au: and cat:.
+type TopicConfig struct {
+	Name      string          `yaml:"name"`
+	Query     string          `yaml:"query"` // Raw arXiv search_query; overrides all/any/not
+	All       []string        `yaml:"all"`   // Every term must match
+	Any       []string        `yaml:"any"`   // At least one term must match
+	Not       []string        `yaml:"not"`   // No term may match
+	Submitted DateRangeConfig `yaml:"submitted"`
+}
+
+// DateRangeConfig is an inclusive range of YYYY-MM-DD dates; either end may be empty.
+type DateRangeConfig struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// UnmarshalYAML accepts a plain string as a topic name.
+func (t *TopicConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		t.Name = value.Value
+		return nil
+	}
+	type plain TopicConfig
+	return value.Decode((*plain)(t))
+}
+
+// IsStructured reports whether the topic defines its own search query.
+func (t TopicConfig) IsStructured() bool {
+	return t.Query != "" || len(t.All) > 0 || len(t.Any) > 0 || len(t.Not) > 0 ||
+		t.Submitted.From != "" || t.Submitted.To != ""
+}
+
+// Bounds parses the range. The end date is inclusive, so to is the last
+// minute of that day. Empty ends yield zero times.
+func (r DateRangeConfig) Bounds() (from, to time.Time, err error) {
+	if r.From != "" {
+		if from, err = time.Parse(dateLayout, r.From); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if r.To != "" {
+		if to, err = time.Parse(dateLayout, r.To); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = to.Add(24*time.Hour - time.Minute)
+	}
+	return from, to, nil
+}
+
+const dateLayout = "2006-01-02"
+
 type FetcherConfig struct {
-	Type string `yaml:"type"`
+	Type            string                `yaml:"type"`
+	Incremental     bool                  `yaml:"incremental"` // Fetch everything since the last successful run
+	Arxiv           ArxivConfig           `yaml:"arxiv"`
+	SemanticScholar SemanticScholarConfig `yaml:"semantic_scholar"`
+	PubMed          PubMedConfig          `yaml:"pubmed"`
+	Biorxiv         BiorxivConfig         `yaml:"biorxiv"` // Used by both biorxiv and medrxiv
+	Feed            FeedConfig            `yaml:"feed"`
+	OpenReview      OpenReviewConfig      `yaml:"openreview"`
+}
+
+// ArxivConfig controls how the arxiv fetcher pages and shares results between topics.
+type ArxivConfig struct {
+	PageSize    int `yaml:"page_size"`     // Results per request when fetching incrementally, default 100
+	MaxTotal    int `yaml:"max_total"`     // Safety bound on papers per topic and run, default 1000
+	MinPerTopic int `yaml:"min_per_topic"` // Papers guaranteed to each topic, default an even share of max_results
+}
+
+// OpenReviewConfig selects the OpenReview venue whose submissions are fetched.
+type OpenReviewConfig struct {
+	Venue      string `yaml:"venue"`      // e.g. "ICLR.cc/2027/Conference"
+	Invitation string `yaml:"invitation"` // Defaults to "<venue>/-/Submission"
+}
+
+// FeedConfig lists the RSS or Atom feeds read by the feed fetcher.
+type FeedConfig struct {
+	URLs []string `yaml:"urls"`
+	Days int      `yaml:"days"` // Only entries published in the last N days (0 = no limit)
+}
+
+type BiorxivConfig struct {
+	Days int `yaml:"days"` // Size of the date interval scanned, ending today
+}
+
+type PubMedConfig struct {
+	APIKey string `yaml:"api_key"`
+	Email  string `yaml:"email"` // Contact address NCBI asks API users to provide
+	Days   int    `yaml:"days"`  // Only papers published in the last N days
+}
+
+type SemanticScholarConfig struct {
+	APIKey string   `yaml:"api_key"`
+	Days   int      `yaml:"days"`   // Only papers published in the last N days
+	Venues []string `yaml:"venues"` // Optional venue filter, e.g. ["Nature", "ICML"]
+}
+
+// StateConfig controls the seen-paper store that keeps digests from repeating papers.
+type StateConfig struct {
+	Type          string `yaml:"type"` // none | file
+	Path          string `yaml:"path"`
+	RetentionDays *int   `yaml:"retention_days"` // Unset means 30; 0 keeps papers forever
 }
 
 type SummarizerConfig struct {
-	Type      string `yaml:"type"`
+	Type      string `yaml:"type"` // anthropic | openai | ollama | extractive
 	Model     string `yaml:"model"`
 	APIKey    string `yaml:"api_key"`
 	MaxTokens int    `yaml:"max_tokens"`
+
+	BaseURL string `yaml:"base_url"` // openai and ollama, e.g. "https://gateway.internal/v1"
+
+	// OpenAI-compatible endpoints only
+	Headers  map[string]string `yaml:"headers"`   // Extra request headers
+	JSONMode bool              `yaml:"json_mode"` // Ask for response_format json_object
+
+	// Ollama only
+	API         string   `yaml:"api"`         // chat | generate
+	NumCtx      int      `yaml:"num_ctx"`     // Context window in tokens; 0 keeps the model default
+	Temperature *float64 `yaml:"temperature"` // Unset keeps the model default
+
+	Mode      string          `yaml:"mode"`       // single | map_reduce
+	MapReduce MapReduceConfig `yaml:"map_reduce"` // Used in map_reduce mode
+}
+
+// MapReduceConfig controls two-phase summarization: batches of papers are
+// scored and summarized in parallel, then a final call selects top_n papers
+// from the best-scored candidates and writes the overview.
+type MapReduceConfig struct {
+	BatchSize       int `yaml:"batch_size"`        // Papers per map call
+	Concurrency     int `yaml:"concurrency"`       // Map calls in flight at once
+	Shortlist       int `yaml:"shortlist"`         // Candidates passed to the reduce call; 0 means 3 × top_n
+	MapMaxTokens    int `yaml:"map_max_tokens"`    // max_tokens of each map call
+	ReduceMaxTokens int `yaml:"reduce_max_tokens"` // max_tokens of the reduce call
 }
 
 type PublisherConfig struct {
@@ -54,7 +230,16 @@ type EmailConfig struct {
 }
 
 type WebConfig struct {
-	Addr string `yaml:"addr"`
+	Addr       string        `yaml:"addr"`
+	ArchiveDir string        `yaml:"archive_dir"` // Where published digests are kept, default data/archive
+	BaseURL    string        `yaml:"base_url"`    // Public URL of the server, used for links in feeds
+	Feed       WebFeedConfig `yaml:"feed"`
+}
+
+// WebFeedConfig controls the Atom/RSS feeds served by the web publisher.
+type WebFeedConfig struct {
+	Mode  string `yaml:"mode"`  // paper | digest
+	Limit int    `yaml:"limit"` // Number of recent digests to include
 }
 
 var envVarRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
@@ -73,13 +258,23 @@ func expandEnvVars(s string) string {
 // GetTopics returns the topics to be used. If Topics is specified, it takes precedence.
 // Otherwise, it returns a slice containing the single Topic for backward compatibility.
 func (c *Config) GetTopics() []string {
+	specs := c.GetTopicSpecs()
+	names := make([]string, len(specs))
+	for i, t := range specs {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// GetTopicSpecs returns the full topic definitions, with the same precedence as GetTopics.
+func (c *Config) GetTopicSpecs() []TopicConfig {
 	if len(c.Topics) > 0 {
 		return c.Topics
 	}
 	if c.Topic != "" {
-		return []string{c.Topic}
+		return []TopicConfig{{Name: c.Topic}}
 	}
-	return []string{}
+	return []TopicConfig{}
 }
 
 // GetTopicsString returns a comma-separated string of all topics for display purposes.
@@ -87,6 +282,25 @@ func (c *Config) GetTopicsString() string {
 	return strings.Join(c.GetTopics(), ", ")
 }
 
+// GetSummarizers returns the summarizers to try, in order. If Summarizers is
+// specified, it takes precedence. Otherwise, it returns a slice containing the
+// single Summarizer for backward compatibility.
+func (c *Config) GetSummarizers() []SummarizerConfig {
+	if len(c.Summarizers) > 0 {
+		return c.Summarizers
+	}
+	return []SummarizerConfig{c.Summarizer}
+}
+
+// GetPublishers returns the publishers to be used. If Publishers is specified, it takes precedence.
+// Otherwise, it returns a slice containing the single Publisher for backward compatibility.
+func (c *Config) GetPublishers() []PublisherConfig {
+	if len(c.Publishers) > 0 {
+		return c.Publishers
+	}
+	return []PublisherConfig{c.Publisher}
+}
+
 func setDefaults(cfg *Config) {
 	if cfg.Language == "" {
 		cfg.Language = "en"
@@ -103,62 +317,337 @@ func setDefaults(cfg *Config) {
 	if cfg.Fetcher.Type == "" {
 		cfg.Fetcher.Type = "arxiv"
 	}
-	if cfg.Summarizer.Type == "" {
-		cfg.Summarizer.Type = "anthropic"
+	if cfg.Fetcher.SemanticScholar.Days == 0 {
+		cfg.Fetcher.SemanticScholar.Days = 30
+	}
+	if cfg.Fetcher.PubMed.Days == 0 {
+		cfg.Fetcher.PubMed.Days = 7
+	}
+	if cfg.Fetcher.Biorxiv.Days == 0 {
+		cfg.Fetcher.Biorxiv.Days = 2
+	}
+	setSummarizerDefaults(&cfg.Summarizer)
+	for i := range cfg.Summarizers {
+		setSummarizerDefaults(&cfg.Summarizers[i])
+	}
+	if cfg.Cost.OverBudget != nil {
+		setSummarizerDefaults(cfg.Cost.OverBudget)
+	}
+	if cfg.State.Type == "" {
+		cfg.State.Type = "none"
+	}
+	if cfg.State.Path == "" {
+		cfg.State.Path = "daily-feed-state.json"
+	}
+	if cfg.State.RetentionDays == nil {
+		days := 30
+		cfg.State.RetentionDays = &days
+	}
+	if cfg.FullText.CacheDir == "" {
+		cfg.FullText.CacheDir = "fulltext-cache"
+	}
+	if cfg.FullText.MaxDownloads == 0 {
+		cfg.FullText.MaxDownloads = 10
+	}
+	if cfg.FullText.MaxChars == 0 {
+		cfg.FullText.MaxChars = 4000
+	}
+	if cfg.SummaryCache.Dir == "" {
+		cfg.SummaryCache.Dir = "summary-cache"
+	}
+	setPublisherDefaults(&cfg.Publisher)
+	for i := range cfg.Publishers {
+		setPublisherDefaults(&cfg.Publishers[i])
+	}
+}
+
+func setSummarizerDefaults(sc *SummarizerConfig) {
+	if sc.Type == "" {
+		sc.Type = "anthropic"
+	}
+	if sc.Model == "" && sc.Type == "anthropic" {
+		sc.Model = "claude-sonnet-4-20250514"
+	}
+	if sc.BaseURL == "" && sc.Type == "openai" {
+		sc.BaseURL = "https://api.openai.com/v1"
+	}
+	if sc.Type == "ollama" {
+		if sc.BaseURL == "" {
+			sc.BaseURL = "http://localhost:11434"
+		}
+		if sc.API == "" {
+			sc.API = "chat"
+		}
+	}
+	if sc.MaxTokens == 0 {
+		sc.MaxTokens = 4096
+	}
+	if sc.Mode == "" {
+		sc.Mode = "single"
+	}
+	if sc.Mode == "map_reduce" {
+		if sc.MapReduce.BatchSize == 0 {
+			sc.MapReduce.BatchSize = 20
+		}
+		if sc.MapReduce.Concurrency == 0 {
+			sc.MapReduce.Concurrency = 4
+		}
+		if sc.MapReduce.MapMaxTokens == 0 {
+			sc.MapReduce.MapMaxTokens = 4096
+		}
+		if sc.MapReduce.ReduceMaxTokens == 0 {
+			sc.MapReduce.ReduceMaxTokens = 2048
+		}
 	}
-	if cfg.Summarizer.Model == "" {
-		cfg.Summarizer.Model = "claude-sonnet-4-20250514"
+}
+
+func setPublisherDefaults(p *PublisherConfig) {
+	if p.Type == "" {
+		p.Type = "stdout"
 	}
-	if cfg.Summarizer.MaxTokens == 0 {
-		cfg.Summarizer.MaxTokens = 4096
+	if p.Web.Addr == "" {
+		p.Web.Addr = ":8080"
 	}
-	if cfg.Publisher.Type == "" {
-		cfg.Publisher.Type = "stdout"
+	if p.Web.ArchiveDir == "" {
+		p.Web.ArchiveDir = filepath.Join("data", "archive")
 	}
-	if cfg.Publisher.Web.Addr == "" {
-		cfg.Publisher.Web.Addr = ":8080"
+	if p.Web.Feed.Mode == "" {
+		p.Web.Feed.Mode = "digest"
 	}
-	if cfg.Publisher.Email.SMTPPort == 0 {
-		cfg.Publisher.Email.SMTPPort = 587
+	if p.Web.Feed.Limit == 0 {
+		p.Web.Feed.Limit = 20
+	}
+	if p.Email.SMTPPort == 0 {
+		p.Email.SMTPPort = 587
 	}
 }
 
+// validateLanguage checks that language has a message catalog, built in or
+// in localesDir.
+func validateLanguage(language, localesDir string) error {
+	if slices.Contains(i18n.Builtin(), language) {
+		return nil
+	}
+	if localesDir != "" && language != "" && !strings.ContainsAny(language, `/\.`) {
+		if _, err := os.Stat(filepath.Join(localesDir, language+".yaml")); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("config: unsupported language %q (supported: %s; add %s.yaml to locales_dir for others)",
+		language, strings.Join(i18n.Builtin(), ", "), language)
+}
+
 func validate(cfg *Config) error {
 	topics := cfg.GetTopics()
 	if len(topics) == 0 {
 		return fmt.Errorf("config: at least one topic is required (use 'topic' for single topic or 'topics' for multiple)")
 	}
-	if cfg.Language != "en" && cfg.Language != "ja" {
-		return fmt.Errorf("config: unsupported language %q (supported: en, ja)", cfg.Language)
+	for i, t := range cfg.Topics {
+		if err := validateTopic(fmt.Sprintf("topics[%d]", i), t, cfg.Fetcher.Type); err != nil {
+			return err
+		}
+	}
+	if err := validateLanguage(cfg.Language, cfg.LocalesDir); err != nil {
+		return err
+	}
+	switch cfg.Fetcher.Type {
+	case "arxiv", "semanticscholar", "pubmed", "biorxiv", "medrxiv":
+	case "feed":
+		if len(cfg.Fetcher.Feed.URLs) == 0 {
+			return fmt.Errorf("config: fetcher.feed.urls is required for feed fetcher")
+		}
+	case "openreview":
+		if cfg.Fetcher.OpenReview.Venue == "" {
+			return fmt.Errorf("config: fetcher.openreview.venue is required for openreview fetcher")
+		}
+	default:
+		return fmt.Errorf("config: unsupported fetcher type %q (supported: arxiv, semanticscholar, pubmed, biorxiv, medrxiv, feed, openreview)", cfg.Fetcher.Type)
+	}
+	if len(cfg.Summarizers) > 0 {
+		for i, sc := range cfg.Summarizers {
+			if err := validateSummarizer(fmt.Sprintf("summarizers[%d]", i), sc); err != nil {
+				return err
+			}
+		}
+	} else if err := validateSummarizer("summarizer", cfg.Summarizer); err != nil {
+		return err
+	}
+	switch cfg.State.Type {
+	case "none", "file":
+	default:
+		return fmt.Errorf("config: unsupported state type %q (supported: none, file)", cfg.State.Type)
+	}
+	if cfg.Fetcher.Incremental {
+		if cfg.Fetcher.Type != "arxiv" {
+			return fmt.Errorf("config: fetcher.incremental is only supported by the arxiv fetcher")
+		}
+		if cfg.State.Type != "file" {
+			return fmt.Errorf("config: fetcher.incremental requires state.type \"file\" to remember the last run")
+		}
+	}
+	if cfg.Fetcher.Arxiv.PageSize < 0 || cfg.Fetcher.Arxiv.MaxTotal < 0 {
+		return fmt.Errorf("config: fetcher.arxiv.page_size and max_total must not be negative")
+	}
+	if cfg.Fetcher.Arxiv.MinPerTopic < 0 {
+		return fmt.Errorf("config: fetcher.arxiv.min_per_topic must not be negative")
+	}
+	if n := cfg.Fetcher.Arxiv.MinPerTopic; n*len(topics) > cfg.MaxResults {
+		return fmt.Errorf("config: fetcher.arxiv.min_per_topic %d for %d topics exceeds max_results %d", n, len(topics), cfg.MaxResults)
+	}
+	if cfg.Fetcher.Feed.Days < 0 {
+		return fmt.Errorf("config: fetcher.feed.days must not be negative")
+	}
+	if *cfg.State.RetentionDays < 0 {
+		return fmt.Errorf("config: state.retention_days must not be negative")
+	}
+	if cfg.FullText.MaxDownloads < 0 || cfg.FullText.MaxChars < 0 {
+		return fmt.Errorf("config: full_text.max_downloads and max_chars must not be negative")
+	}
+	if err := validateCost(cfg); err != nil {
+		return err
+	}
+	if cfg.Prompts.Version != "" && cfg.Prompts.Dir == "" {
+		return fmt.Errorf("config: prompts.version requires prompts.dir")
+	}
+	if len(cfg.Publishers) == 0 {
+		return validatePublisher("publisher", cfg.Publisher)
 	}
-	if cfg.Fetcher.Type != "arxiv" {
-		return fmt.Errorf("config: unsupported fetcher type %q (supported: arxiv)", cfg.Fetcher.Type)
+	webAddrs := make(map[string]string)
+	archiveDirs := make(map[string]string)
+	for i, p := range cfg.Publishers {
+		field := fmt.Sprintf("publishers[%d]", i)
+		if err := validatePublisher(field, p); err != nil {
+			return err
+		}
+		if p.Type == "web" {
+			if other, ok := webAddrs[p.Web.Addr]; ok {
+				return fmt.Errorf("config: %s.web.addr %q is already used by %s", field, p.Web.Addr, other)
+			}
+			webAddrs[p.Web.Addr] = field
+			dir := filepath.Clean(p.Web.ArchiveDir)
+			if other, ok := archiveDirs[dir]; ok {
+				return fmt.Errorf("config: %s.web.archive_dir %q is already used by %s", field, p.Web.ArchiveDir, other)
+			}
+			archiveDirs[dir] = field
+		}
+	}
+	return nil
+}
+
+// validateCost checks the price table and the monthly budget.
+func validateCost(cfg *Config) error {
+	for model, price := range cfg.Cost.Prices {
+		if price.Input < 0 || price.Output < 0 {
+			return fmt.Errorf("config: cost.prices[%q] must not be negative", model)
+		}
+	}
+	if cfg.Cost.MonthlyBudget < 0 {
+		return fmt.Errorf("config: cost.monthly_budget must not be negative")
+	}
+	if cfg.Cost.MonthlyBudget > 0 && cfg.RunLog == "" {
+		return fmt.Errorf("config: cost.monthly_budget requires run_log, where the cost of past runs is kept")
+	}
+	if cfg.Cost.OverBudget != nil {
+		if cfg.Cost.MonthlyBudget == 0 {
+			return fmt.Errorf("config: cost.over_budget requires cost.monthly_budget")
+		}
+		return validateSummarizer("cost.over_budget", *cfg.Cost.OverBudget)
+	}
+	return nil
+}
+
+// validateTopic checks a single topic entry; field names it in error messages.
+func validateTopic(field string, t TopicConfig, fetcherType string) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("config: %s.name is required", field)
+	}
+	if !t.IsStructured() {
+		return nil
+	}
+	if fetcherType != "arxiv" {
+		return fmt.Errorf("config: %s: structured queries are only supported by the arxiv fetcher", field)
+	}
+	from, to, err := t.Submitted.Bounds()
+	if err != nil {
+		return fmt.Errorf("config: %s.submitted dates must be YYYY-MM-DD: %w", field, err)
 	}
-	if cfg.Summarizer.Type != "anthropic" {
-		return fmt.Errorf("config: unsupported summarizer type %q (supported: anthropic)", cfg.Summarizer.Type)
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return fmt.Errorf("config: %s.submitted.to is before submitted.from", field)
 	}
-	if cfg.Summarizer.APIKey == "" {
-		return fmt.Errorf("config: summarizer.api_key is required (set ANTHROPIC_API_KEY env var)")
+	return nil
+}
+
+// validateSummarizer checks a single summarizer entry. field is the config
+// path of the entry (e.g. "summarizer" or "summarizers[1]") used in error
+// messages.
+func validateSummarizer(field string, sc SummarizerConfig) error {
+	switch sc.Type {
+	case "anthropic":
+		if sc.APIKey == "" {
+			return fmt.Errorf("config: %s.api_key is required (set ANTHROPIC_API_KEY env var)", field)
+		}
+	case "openai":
+		if sc.Model == "" {
+			return fmt.Errorf("config: %s.model is required for openai summarizer", field)
+		}
+	case "ollama":
+		if sc.Model == "" {
+			return fmt.Errorf("config: %s.model is required for ollama summarizer", field)
+		}
+		if sc.API != "chat" && sc.API != "generate" {
+			return fmt.Errorf("config: unsupported %s.api %q (supported: chat, generate)", field, sc.API)
+		}
+		if sc.NumCtx < 0 {
+			return fmt.Errorf("config: %s.num_ctx must not be negative", field)
+		}
+	case "extractive":
+		// Runs locally without a model or key
+	default:
+		return fmt.Errorf("config: unsupported %s type %q (supported: anthropic, openai, ollama, extractive)", field, sc.Type)
 	}
-	switch cfg.Publisher.Type {
+	switch sc.Mode {
+	case "single":
+	case "map_reduce":
+		if sc.Type == "extractive" {
+			return fmt.Errorf("config: %s.mode map_reduce requires an LLM summarizer", field)
+		}
+		mr := sc.MapReduce
+		if mr.BatchSize < 0 || mr.Concurrency < 0 || mr.Shortlist < 0 || mr.MapMaxTokens < 0 || mr.ReduceMaxTokens < 0 {
+			return fmt.Errorf("config: %s.map_reduce settings must not be negative", field)
+		}
+	default:
+		return fmt.Errorf("config: unsupported %s.mode %q (supported: single, map_reduce)", field, sc.Mode)
+	}
+	return nil
+}
+
+// validatePublisher checks a single publisher entry. field is the config path
+// of the entry (e.g. "publisher" or "publishers[1]") used in error messages.
+func validatePublisher(field string, p PublisherConfig) error {
+	switch p.Type {
 	case "stdout", "email", "web", "discord":
 	default:
-		return fmt.Errorf("config: unsupported publisher type %q (supported: stdout, email, web, discord)", cfg.Publisher.Type)
+		return fmt.Errorf("config: unsupported %s type %q (supported: stdout, email, web, discord)", field, p.Type)
+	}
+	if p.Type == "discord" {
+		if p.Discord.WebhookURL == "" {
+			return fmt.Errorf("config: %s.discord.webhook_url is required for discord publisher", field)
+		}
 	}
-	if cfg.Publisher.Type == "discord" {
-		if cfg.Publisher.Discord.WebhookURL == "" {
-			return fmt.Errorf("config: publisher.discord.webhook_url is required for discord publisher")
+	if p.Type == "web" {
+		if p.Web.Feed.Mode != "paper" && p.Web.Feed.Mode != "digest" {
+			return fmt.Errorf("config: unsupported %s.web.feed.mode %q (supported: paper, digest)", field, p.Web.Feed.Mode)
 		}
 	}
-	if cfg.Publisher.Type == "email" {
-		if cfg.Publisher.Email.SMTPHost == "" {
-			return fmt.Errorf("config: publisher.email.smtp_host is required for email publisher")
+	if p.Type == "email" {
+		if p.Email.SMTPHost == "" {
+			return fmt.Errorf("config: %s.email.smtp_host is required for email publisher", field)
 		}
-		if len(cfg.Publisher.Email.To) == 0 {
-			return fmt.Errorf("config: publisher.email.to is required for email publisher")
+		if len(p.Email.To) == 0 {
+			return fmt.Errorf("config: %s.email.to is required for email publisher", field)
 		}
-		if cfg.Publisher.Email.From == "" {
-			return fmt.Errorf("config: publisher.email.from is required for email publisher")
+		if p.Email.From == "" {
+			return fmt.Errorf("config: %s.email.from is required for email publisher", field)
 		}
 	}
 	return nil
@@ -186,4 +675,4 @@ func Load(path string) (*Config, error) {
 	}
 
 	return &cfg, nil
-}
\ No newline at end of file
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -176,9 +177,24 @@ summarizer:
 	if cfg.Publisher.Web.Addr != ":8080" {
 		t.Errorf("Expected default web addr ':8080', got '%s'", cfg.Publisher.Web.Addr)
 	}
+	if cfg.Publisher.Web.ArchiveDir != filepath.Join("data", "archive") {
+		t.Errorf("Expected default web archive dir 'data/archive', got '%s'", cfg.Publisher.Web.ArchiveDir)
+	}
 	if cfg.Publisher.Email.SMTPPort != 587 {
 		t.Errorf("Expected default SMTP port 587, got %d", cfg.Publisher.Email.SMTPPort)
 	}
+	if cfg.State.Type != "none" {
+		t.Errorf("Expected default state type 'none', got '%s'", cfg.State.Type)
+	}
+	if *cfg.State.RetentionDays != 30 {
+		t.Errorf("Expected default state retention 30 days, got %d", *cfg.State.RetentionDays)
+	}
+	if cfg.FullText.Enabled || cfg.FullText.MaxDownloads != 10 || cfg.FullText.MaxChars != 4000 {
+		t.Errorf("Expected full text disabled with 10 downloads and 4000 chars, got %+v", cfg.FullText)
+	}
+	if cfg.SummaryCache.Enabled || cfg.SummaryCache.Dir != "summary-cache" {
+		t.Errorf("Expected summary cache disabled in summary-cache, got %+v", cfg.SummaryCache)
+	}
 }
 
 func TestLanguageValidation(t *testing.T) {
@@ -189,8 +205,9 @@ func TestLanguageValidation(t *testing.T) {
 	}{
 		{"valid english", "en", false},
 		{"valid japanese", "ja", false},
-		{"invalid language", "fr", true},
-		{"invalid language", "zh", true},
+		{"valid french", "fr", false},
+		{"valid chinese", "zh", false},
+		{"invalid language", "xx", true},
 	}
 
 	for _, tt := range tests {
@@ -418,4 +435,832 @@ func TestEnvVarExpansionUnset(t *testing.T) {
 	if expanded != input {
 		t.Errorf("Expected unset var to remain as-is, got '%s'", expanded)
 	}
-}
\ No newline at end of file
+}
+
+func TestPublishersList(t *testing.T) {
+	tmpConfig := `
+topic: test
+summarizer:
+  api_key: test_key
+publishers:
+  - type: discord
+    discord:
+      webhook_url: https://discord.example.com/a
+  - type: discord
+    discord:
+      webhook_url: https://discord.example.com/b
+  - type: email
+    email:
+      smtp_host: smtp.example.com
+      from: sender@example.com
+      to: [recipient@example.com]
+  - type: web
+`
+	tmpfile, err := os.CreateTemp("", "publishers_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(tmpConfig)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	cfg, err := Load(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	pubs := cfg.GetPublishers()
+	if len(pubs) != 4 {
+		t.Fatalf("Expected 4 publishers, got %d", len(pubs))
+	}
+	if pubs[0].Discord.WebhookURL != "https://discord.example.com/a" || pubs[1].Discord.WebhookURL != "https://discord.example.com/b" {
+		t.Errorf("Expected each discord publisher to keep its own webhook, got %q and %q", pubs[0].Discord.WebhookURL, pubs[1].Discord.WebhookURL)
+	}
+	if pubs[2].Email.SMTPPort != 587 {
+		t.Errorf("Expected default SMTP port 587 for list entry, got %d", pubs[2].Email.SMTPPort)
+	}
+	if pubs[3].Web.Addr != ":8080" {
+		t.Errorf("Expected default web addr ':8080' for list entry, got '%s'", pubs[3].Web.Addr)
+	}
+}
+
+func TestGetPublishersLegacy(t *testing.T) {
+	cfg := &Config{Publisher: PublisherConfig{Type: "stdout"}}
+
+	pubs := cfg.GetPublishers()
+	if len(pubs) != 1 || pubs[0].Type != "stdout" {
+		t.Errorf("Expected legacy publisher to be returned, got %v", pubs)
+	}
+}
+
+func TestPublishersValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "invalid entry",
+			config: `
+topic: test
+summarizer:
+  api_key: test_key
+publishers:
+  - type: stdout
+  - type: discord
+`,
+			wantErr: "publishers[1].discord.webhook_url is required",
+		},
+		{
+			name: "unsupported type",
+			config: `
+topic: test
+summarizer:
+  api_key: test_key
+publishers:
+  - type: carrier-pigeon
+`,
+			wantErr: "unsupported publishers[0] type",
+		},
+		{
+			name: "duplicate web addr",
+			config: `
+topic: test
+summarizer:
+  api_key: test_key
+publishers:
+  - type: web
+  - type: web
+`,
+			wantErr: "already used by publishers[0]",
+		},
+		{
+			name: "shared archive dir",
+			config: `
+topic: test
+summarizer:
+  api_key: test_key
+publishers:
+  - type: web
+    web:
+      addr: ":8080"
+  - type: web
+    web:
+      addr: ":8081"
+`,
+			wantErr: `publishers[1].web.archive_dir "data/archive" is already used by publishers[0]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpfile, err := os.CreateTemp("", "publishers_config_*.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+			if _, err := tmpfile.Write([]byte(tt.config)); err != nil {
+				t.Fatalf("Failed to write temp config: %v", err)
+			}
+			tmpfile.Close()
+
+			_, err = Load(tmpfile.Name())
+			if err == nil {
+				t.Fatalf("Expected validation error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStateValidation(t *testing.T) {
+	cfg := `
+topic: test
+summarizer:
+  api_key: test_key
+state:
+  type: redis
+`
+	tmpfile, err := os.CreateTemp("", "state_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(cfg)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	_, err = Load(tmpfile.Name())
+	if err == nil {
+		t.Fatal("Expected validation error for unsupported state type")
+	}
+	if !strings.Contains(err.Error(), "unsupported state type") {
+		t.Errorf("Expected 'unsupported state type' error, got: %v", err)
+	}
+}
+
+func TestStateRetentionZeroKeepsPapersForever(t *testing.T) {
+	cfg := `
+topic: test
+summarizer:
+  api_key: test_key
+state:
+  type: file
+  retention_days: 0
+`
+	tmpfile, err := os.CreateTemp("", "state_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(cfg)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	loaded, err := Load(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if loaded.State.RetentionDays == nil || *loaded.State.RetentionDays != 0 {
+		t.Errorf("Expected an explicit retention of 0 days, got %v", loaded.State.RetentionDays)
+	}
+}
+
+func TestFetcherTypeValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		fetcherType string
+		wantErr     bool
+	}{
+		{"arxiv", "arxiv", false},
+		{"semantic scholar", "semanticscholar", false},
+		{"pubmed", "pubmed", false},
+		{"biorxiv", "biorxiv", false},
+		{"medrxiv", "medrxiv", false},
+		{"unknown", "scopus", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := `
+topic: test
+summarizer:
+  api_key: test_key
+fetcher:
+  type: ` + tt.fetcherType + `
+`
+			tmpfile, err := os.CreateTemp("", "fetcher_config_*.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+			if _, err := tmpfile.Write([]byte(cfg)); err != nil {
+				t.Fatalf("Failed to write temp config: %v", err)
+			}
+			tmpfile.Close()
+
+			_, err = Load(tmpfile.Name())
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "unsupported fetcher type")) {
+				t.Errorf("Expected 'unsupported fetcher type' error, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error for fetcher type %s: %v", tt.fetcherType, err)
+			}
+		})
+	}
+}
+
+func TestFeedFetcherValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		feed    string
+		wantErr string
+	}{
+		{"with urls", "  feed:\n    urls: [\"https://example.org/toc.rss\"]\n    days: 14\n", ""},
+		{"missing urls", "", "fetcher.feed.urls is required"},
+		{"negative days", "  feed:\n    urls: [\"https://example.org/toc.rss\"]\n    days: -1\n", "fetcher.feed.days must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := `
+topic: test
+summarizer:
+  api_key: test_key
+fetcher:
+  type: feed
+` + tt.feed
+			tmpfile, err := os.CreateTemp("", "feed_config_*.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+			if _, err := tmpfile.Write([]byte(cfg)); err != nil {
+				t.Fatalf("Failed to write temp config: %v", err)
+			}
+			tmpfile.Close()
+
+			loaded, err := Load(tmpfile.Name())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected %q error, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(loaded.Fetcher.Feed.URLs) != 1 || loaded.Fetcher.Feed.Days != 14 {
+				t.Errorf("Unexpected feed config: %+v", loaded.Fetcher.Feed)
+			}
+		})
+	}
+}
+
+func TestOpenReviewFetcherRequiresVenue(t *testing.T) {
+	tmpConfig := `
+topic: test
+summarizer:
+  api_key: test_key
+fetcher:
+  type: openreview
+`
+	tmpfile, err := os.CreateTemp("", "openreview_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(tmpConfig)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	_, err = Load(tmpfile.Name())
+	if err == nil || !strings.Contains(err.Error(), "fetcher.openreview.venue is required") {
+		t.Errorf("Expected missing venue error, got: %v", err)
+	}
+}
+
+func TestStructuredTopics(t *testing.T) {
+	tmpConfig := `
+topics:
+  - "machine learning"
+  - name: "LLM agents"
+    all: ["ti:agent"]
+    any: ["cat:cs.AI", "cat:cs.CL"]
+    not: ["ti:survey"]
+    submitted:
+      from: "2025-01-01"
+      to: "2025-01-31"
+summarizer:
+  api_key: test_key
+`
+	tmpfile, err := os.CreateTemp("", "structured_topics_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(tmpConfig)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	cfg, err := Load(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	topics := cfg.GetTopics()
+	if len(topics) != 2 || topics[0] != "machine learning" || topics[1] != "LLM agents" {
+		t.Errorf("Expected topic names, got %v", topics)
+	}
+
+	specs := cfg.GetTopicSpecs()
+	if specs[0].IsStructured() {
+		t.Error("Expected plain string topic not to be structured")
+	}
+	agents := specs[1]
+	if !agents.IsStructured() || len(agents.Any) != 2 || agents.Not[0] != "ti:survey" {
+		t.Errorf("Unexpected structured topic: %+v", agents)
+	}
+	from, to, err := agents.Submitted.Bounds()
+	if err != nil {
+		t.Fatalf("Bounds returned error: %v", err)
+	}
+	if from.Format("2006-01-02 15:04") != "2025-01-01 00:00" || to.Format("2006-01-02 15:04") != "2025-01-31 23:59" {
+		t.Errorf("Unexpected bounds: %v - %v", from, to)
+	}
+}
+
+func TestStructuredTopicValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		topic   string
+		fetcher string
+		wantErr string
+	}{
+		{"missing name", `{all: ["ti:agent"]}`, "arxiv", "topics[0].name is required"},
+		{"bad date", `{name: x, submitted: {from: "01/02/2025"}}`, "arxiv", "topics[0].submitted dates must be YYYY-MM-DD"},
+		{"reversed range", `{name: x, submitted: {from: "2025-02-01", to: "2025-01-01"}}`, "arxiv", "submitted.to is before submitted.from"},
+		{"other fetcher", `{name: x, all: ["ti:agent"]}`, "pubmed", "only supported by the arxiv fetcher"},
+		{"plain topic with other fetcher", `{name: x}`, "pubmed", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := `
+topics:
+  - ` + tt.topic + `
+fetcher:
+  type: ` + tt.fetcher + `
+summarizer:
+  api_key: test_key
+`
+			tmpfile, err := os.CreateTemp("", "topic_validation_*.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+			if _, err := tmpfile.Write([]byte(cfg)); err != nil {
+				t.Fatalf("Failed to write temp config: %v", err)
+			}
+			tmpfile.Close()
+
+			_, err = Load(tmpfile.Name())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected %q error, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestIncrementalFetchingValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   string
+		wantErr string
+	}{
+		{"arxiv with file state", "fetcher:\n  type: arxiv\n  incremental: true\nstate:\n  type: file\n", ""},
+		{"without state store", "fetcher:\n  type: arxiv\n  incremental: true\n", "requires state.type"},
+		{"unsupported fetcher", "fetcher:\n  type: pubmed\n  incremental: true\nstate:\n  type: file\n", "only supported by the arxiv fetcher"},
+		{"negative page size", "fetcher:\n  type: arxiv\n  arxiv:\n    page_size: -1\n", "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpfile, err := os.CreateTemp("", "incremental_config_*.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+			if _, err := tmpfile.Write([]byte("topic: test\nsummarizer:\n  api_key: test_key\n" + tt.extra)); err != nil {
+				t.Fatalf("Failed to write temp config: %v", err)
+			}
+			tmpfile.Close()
+
+			cfg, err := Load(tmpfile.Name())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				if !cfg.Fetcher.Incremental {
+					t.Error("Expected fetcher.incremental to be true")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMinPerTopicValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxResults  string
+		minPerTopic string
+		wantErr     string
+	}{
+		{"fits max_results", "max_results: 10\n", "5", ""},
+		{"fits default max_results", "", "10", ""},
+		{"exceeds max_results", "max_results: 5\n", "3", "min_per_topic 3 for 2 topics exceeds max_results 5"},
+		{"exceeds default max_results", "", "11", "exceeds max_results 20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpfile, err := os.CreateTemp("", "min_per_topic_config_*.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+			cfg := "topics: [llm, agents]\n" + tt.maxResults + "summarizer:\n  api_key: test_key\nfetcher:\n  type: arxiv\n  arxiv:\n    min_per_topic: " + tt.minPerTopic + "\n"
+			if _, err := tmpfile.Write([]byte(cfg)); err != nil {
+				t.Fatalf("Failed to write temp config: %v", err)
+			}
+			tmpfile.Close()
+
+			_, err = Load(tmpfile.Name())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOpenAISummarizerConfig(t *testing.T) {
+	tmpConfig := `
+topic: test
+summarizer:
+  type: openai
+  model: llama-3-70b
+  base_url: https://gateway.example.com/v1
+  headers:
+    X-Team: research
+  json_mode: true
+`
+	tmpfile, err := os.CreateTemp("", "openai_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(tmpConfig)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	cfg, err := Load(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Expected no API key to be required, got: %v", err)
+	}
+	sc := cfg.Summarizer
+	if sc.Model != "llama-3-70b" || sc.BaseURL != "https://gateway.example.com/v1" || !sc.JSONMode {
+		t.Errorf("Unexpected summarizer config: %+v", sc)
+	}
+	if sc.Headers["X-Team"] != "research" {
+		t.Errorf("Expected header X-Team, got %v", sc.Headers)
+	}
+}
+
+func TestOpenAISummarizerRequiresModel(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "openai_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte("topic: test\nsummarizer:\n  type: openai\n")); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	_, err = Load(tmpfile.Name())
+	if err == nil || !strings.Contains(err.Error(), "summarizer.model is required") {
+		t.Errorf("Expected missing model error, got: %v", err)
+	}
+}
+
+func TestOllamaSummarizerConfig(t *testing.T) {
+	tmpConfig := `
+topic: test
+summarizer:
+  type: ollama
+  model: llama3.2
+  num_ctx: 16384
+  temperature: 0
+`
+	tmpfile, err := os.CreateTemp("", "ollama_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(tmpConfig)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	cfg, err := Load(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Expected no API key to be required, got: %v", err)
+	}
+	sc := cfg.Summarizer
+	if sc.BaseURL != "http://localhost:11434" || sc.API != "chat" || sc.NumCtx != 16384 {
+		t.Errorf("Unexpected summarizer config: %+v", sc)
+	}
+	if sc.Temperature == nil || *sc.Temperature != 0 {
+		t.Errorf("Expected an explicit temperature of 0, got %v", sc.Temperature)
+	}
+}
+
+func TestSummarizerChainConfig(t *testing.T) {
+	tmpConfig := `
+topic: test
+summarizers:
+  - type: anthropic
+    api_key: test_key
+  - type: ollama
+    model: llama3.2
+  - type: extractive
+`
+	tmpfile, err := os.CreateTemp("", "chain_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(tmpConfig)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	cfg, err := Load(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Expected the unused legacy summarizer not to be validated, got: %v", err)
+	}
+	scs := cfg.GetSummarizers()
+	if len(scs) != 3 {
+		t.Fatalf("Expected 3 summarizers, got %d", len(scs))
+	}
+	if scs[0].Model != "claude-sonnet-4-20250514" || scs[1].BaseURL != "http://localhost:11434" || scs[2].Type != "extractive" {
+		t.Errorf("Expected defaults applied to each entry, got %+v", scs)
+	}
+}
+
+func TestSummarizerChainValidation(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "chain_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte("topic: test\nsummarizers:\n  - type: extractive\n  - type: openai\n")); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	_, err = Load(tmpfile.Name())
+	if err == nil || !strings.Contains(err.Error(), "summarizers[1].model is required") {
+		t.Errorf("Expected missing model error for the second entry, got: %v", err)
+	}
+}
+
+func TestMapReduceSummarizerConfig(t *testing.T) {
+	tmpConfig := `
+topic: test
+max_results: 200
+summarizer:
+  type: anthropic
+  api_key: test_key
+  mode: map_reduce
+  map_reduce:
+    batch_size: 25
+    reduce_max_tokens: 1024
+`
+	tmpfile, err := os.CreateTemp("", "mapreduce_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(tmpConfig)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	cfg, err := Load(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	mr := cfg.Summarizer.MapReduce
+	if mr.BatchSize != 25 || mr.Concurrency != 4 || mr.Shortlist != 0 || mr.MapMaxTokens != 4096 || mr.ReduceMaxTokens != 1024 {
+		t.Errorf("Unexpected map_reduce config: %+v", mr)
+	}
+}
+
+func TestMapReduceSummarizerValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "unknown mode",
+			yaml:    "topic: test\nsummarizer:\n  type: extractive\n  mode: batch\n",
+			wantErr: `unsupported summarizer.mode "batch"`,
+		},
+		{
+			name:    "extractive",
+			yaml:    "topic: test\nsummarizer:\n  type: extractive\n  mode: map_reduce\n",
+			wantErr: "summarizer.mode map_reduce requires an LLM summarizer",
+		},
+		{
+			name:    "negative concurrency",
+			yaml:    "topic: test\nsummarizer:\n  type: ollama\n  model: llama3.2\n  mode: map_reduce\n  map_reduce:\n    concurrency: -1\n",
+			wantErr: "summarizer.map_reduce settings must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpfile, err := os.CreateTemp("", "mapreduce_config_*.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+			if _, err := tmpfile.Write([]byte(tt.yaml)); err != nil {
+				t.Fatalf("Failed to write temp config: %v", err)
+			}
+			tmpfile.Close()
+
+			_, err = Load(tmpfile.Name())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCostConfig(t *testing.T) {
+	tmpConfig := `
+topic: test
+run_log: runs.jsonl
+summarizer:
+  type: anthropic
+  api_key: test_key
+cost:
+  show_in_digest: true
+  prices:
+    claude-sonnet-4-20250514: {input: 3, output: 15}
+    "openai/gpt-4o-mini": {input: 0.15, output: 0.6}
+  monthly_budget: 20
+  over_budget:
+    type: anthropic
+    api_key: test_key
+    model: claude-3-5-haiku-20241022
+`
+	tmpfile, err := os.CreateTemp("", "cost_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte(tmpConfig)); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	cfg, err := Load(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if !cfg.Cost.ShowInDigest || cfg.Cost.MonthlyBudget != 20 || len(cfg.Cost.Prices) != 2 {
+		t.Errorf("Unexpected cost config: %+v", cfg.Cost)
+	}
+	if p := cfg.Cost.Prices["openai/gpt-4o-mini"]; p.Input != 0.15 || p.Output != 0.6 {
+		t.Errorf("Unexpected gpt-4o-mini price: %+v", p)
+	}
+	if ob := cfg.Cost.OverBudget; ob == nil || ob.Model != "claude-3-5-haiku-20241022" || ob.MaxTokens != 4096 {
+		t.Errorf("Expected the over-budget summarizer with defaults, got %+v", ob)
+	}
+}
+
+func TestCostValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "negative price",
+			yaml:    "topic: test\nsummarizer:\n  type: extractive\ncost:\n  prices:\n    gpt-4o: {input: -1}\n",
+			wantErr: `cost.prices["gpt-4o"] must not be negative`,
+		},
+		{
+			name:    "budget without run log",
+			yaml:    "topic: test\nsummarizer:\n  type: extractive\ncost:\n  monthly_budget: 10\n",
+			wantErr: "cost.monthly_budget requires run_log",
+		},
+		{
+			name:    "over_budget without budget",
+			yaml:    "topic: test\nsummarizer:\n  type: extractive\ncost:\n  over_budget:\n    type: extractive\n",
+			wantErr: "cost.over_budget requires cost.monthly_budget",
+		},
+		{
+			name:    "invalid over_budget",
+			yaml:    "topic: test\nrun_log: runs.jsonl\nsummarizer:\n  type: extractive\ncost:\n  monthly_budget: 10\n  over_budget:\n    type: openai\n",
+			wantErr: "cost.over_budget.model is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpfile, err := os.CreateTemp("", "cost_config_*.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+			if _, err := tmpfile.Write([]byte(tt.yaml)); err != nil {
+				t.Fatalf("Failed to write temp config: %v", err)
+			}
+			tmpfile.Close()
+
+			_, err = Load(tmpfile.Name())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPromptsVersionRequiresDir(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "prompts_config_*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write([]byte("topic: test\nsummarizer:\n  type: extractive\nprompts:\n  version: tone-v2\n")); err != nil {
+		t.Fatalf("Failed to write temp config: %v", err)
+	}
+	tmpfile.Close()
+
+	_, err = Load(tmpfile.Name())
+	if err == nil || !strings.Contains(err.Error(), "prompts.version requires prompts.dir") {
+		t.Errorf("Expected missing prompts.dir error, got: %v", err)
+	}
+}
+
+func TestLanguageFromLocalesDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "eo.yaml"), []byte("name: Esperanto\nenglish_name: Esperanto\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write catalog: %v", err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+
+	if err := os.WriteFile(path, []byte("topic: test\nlanguage: eo\nlocales_dir: "+dir+"\nsummarizer:\n  type: extractive\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Errorf("Expected a language with a catalog in locales_dir to be valid, got: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("topic: test\nlanguage: eo\nsummarizer:\n  type: extractive\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "unsupported language") {
+		t.Errorf("Expected unsupported language error without locales_dir, got: %v", err)
+	}
+}
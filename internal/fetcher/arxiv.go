@@ -5,10 +5,13 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
-	"sort"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ryosukesatoh/daily-feed/internal/retry"
@@ -21,13 +24,21 @@ type arxivFeed struct {
 	Entries []arxivEntry `xml:"entry"`
 }
 
+// arxivEntry decodes an Atom entry together with the arXiv extension elements
+// in the http://arxiv.org/schemas/atom namespace.
 type arxivEntry struct {
-	Title     string         `xml:"title"`
-	Summary   string         `xml:"summary"`
-	Authors   []arxivAuthor  `xml:"author"`
-	Links     []arxivLink    `xml:"link"`
-	Published string         `xml:"published"`
-	Category  []arxivCategory `xml:"category"`
+	ID              string          `xml:"id"`
+	Title           string          `xml:"title"`
+	Summary         string          `xml:"summary"`
+	Authors         []arxivAuthor   `xml:"author"`
+	Links           []arxivLink     `xml:"link"`
+	Published       string          `xml:"published"`
+	Updated         string          `xml:"updated"`
+	Category        []arxivCategory `xml:"category"`
+	PrimaryCategory arxivCategory   `xml:"http://arxiv.org/schemas/atom primary_category"`
+	DOI             string          `xml:"http://arxiv.org/schemas/atom doi"`
+	JournalRef      string          `xml:"http://arxiv.org/schemas/atom journal_ref"`
+	Comment         string          `xml:"http://arxiv.org/schemas/atom comment"`
 }
 
 type arxivAuthor struct {
@@ -35,26 +46,109 @@ type arxivAuthor struct {
 }
 
 type arxivLink struct {
-	Href string `xml:"href,attr"`
-	Type string `xml:"type,attr"`
-	Rel  string `xml:"rel,attr"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr"`
+	Rel   string `xml:"rel,attr"`
+	Title string `xml:"title,attr"`
 }
 
 type arxivCategory struct {
 	Term string `xml:"term,attr"`
 }
 
+var arxivVersionRegex = regexp.MustCompile(`v(\d+)$`)
+
+// toPaper converts a feed entry into a Paper.
+func (entry arxivEntry) toPaper() Paper {
+	published, _ := time.Parse(time.RFC3339, entry.Published)
+	updated, _ := time.Parse(time.RFC3339, entry.Updated)
+
+	authors := make([]string, len(entry.Authors))
+	for i, a := range entry.Authors {
+		authors[i] = strings.TrimSpace(a.Name)
+	}
+
+	var paperURL, pdfURL string
+	for _, link := range entry.Links {
+		if link.Rel == "alternate" || (link.Type == "text/html" && paperURL == "") {
+			paperURL = link.Href
+		}
+		if link.Title == "pdf" || (link.Type == "application/pdf" && pdfURL == "") {
+			pdfURL = link.Href
+		}
+	}
+	if paperURL == "" && len(entry.Links) > 0 {
+		paperURL = entry.Links[0].Href
+	}
+
+	categories := make([]string, 0, len(entry.Category))
+	for _, c := range entry.Category {
+		categories = append(categories, c.Term)
+	}
+
+	category := entry.PrimaryCategory.Term
+	if category == "" && len(categories) > 0 {
+		category = categories[0]
+	}
+
+	var version int
+	if m := arxivVersionRegex.FindStringSubmatch(strings.TrimSpace(entry.ID)); m != nil {
+		version, _ = strconv.Atoi(m[1])
+	}
+
+	return Paper{
+		ID:         parseArxivID(entry.ID),
+		Title:      strings.TrimSpace(entry.Title),
+		Authors:    authors,
+		Abstract:   strings.TrimSpace(entry.Summary),
+		URL:        paperURL,
+		Published:  published,
+		Category:   category,
+		Categories: categories,
+		Version:    version,
+		Updated:    updated,
+		DOI:        strings.TrimSpace(entry.DOI),
+		JournalRef: collapseSpace(entry.JournalRef),
+		Comment:    collapseSpace(entry.Comment),
+		PDFURL:     pdfURL,
+	}
+}
+
+// collapseSpace trims s and replaces the line breaks and runs of spaces
+// found in wrapped Atom text with single spaces.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// parseArxivID extracts the versionless arXiv ID from an entry ID such as
+// "http://arxiv.org/abs/2401.12345v2" or "http://arxiv.org/abs/hep-th/9901001v1".
+func parseArxivID(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if idx := strings.Index(raw, "/abs/"); idx >= 0 {
+		raw = raw[idx+len("/abs/"):]
+	}
+	return arxivVersionRegex.ReplaceAllString(raw, "")
+}
+
 // ArxivFetcher fetches papers from the arXiv API.
 type ArxivFetcher struct {
-	client     *http.Client
-	baseURL    string
+	client      *http.Client
+	baseURL     string
+	queries     map[string]ArxivQuery // Structured queries by topic name
+	pageSize    int                   // Results per page when paging with FetchSince
+	maxTotal    int                   // Safety bound on the papers FetchSince returns per topic
+	minPerTopic int                   // Papers guaranteed to each topic by FetchMultiple; 0 means an even share
+	limiter     *rateLimiter          // Shared by concurrent topic queries, as the arXiv API terms ask
 	retryConfig retry.Config
 }
 
 func NewArxivFetcher() *ArxivFetcher {
 	return &ArxivFetcher{
-		client:  &http.Client{Timeout: 30 * time.Second},
-		baseURL: "http://export.arxiv.org/api/query",
+		client:   &http.Client{Timeout: 30 * time.Second},
+		baseURL:  "http://export.arxiv.org/api/query",
+		pageSize: 100,
+		maxTotal: 1000,
+		limiter:  newRateLimiter(3 * time.Second),
 		retryConfig: retry.Config{
 			MaxRetries: 3,
 			BaseDelay:  1 * time.Second,
@@ -62,25 +156,81 @@ func NewArxivFetcher() *ArxivFetcher {
 	}
 }
 
+// SetPaging sets the page size and the upper bound on the total number of
+// papers FetchSince returns. Non-positive values keep the defaults.
+func (f *ArxivFetcher) SetPaging(pageSize, maxTotal int) {
+	if pageSize > 0 {
+		f.pageSize = pageSize
+	}
+	if maxTotal > 0 {
+		f.maxTotal = maxTotal
+	}
+}
+
+// SetMinPerTopic sets how many papers each topic is guaranteed when
+// FetchMultiple trims the merged results, so that a busy topic cannot crowd
+// out a quiet one. Zero splits maxResults evenly between the topics. When
+// maxResults cannot hold n papers for every topic, each topic is guaranteed
+// only its even share; the config rejects such settings.
+func (f *ArxivFetcher) SetMinPerTopic(n int) {
+	f.minPerTopic = n
+}
+
+// SetQueries registers structured queries by topic name. Topics without a
+// query keep searching all fields for the topic text.
+func (f *ArxivFetcher) SetQueries(queries map[string]ArxivQuery) {
+	f.queries = queries
+}
+
+// searchQuery returns the compiled structured query of topic, if any.
+func (f *ArxivFetcher) searchQuery(topic string) (string, bool) {
+	q, ok := f.queries[topic]
+	if !ok {
+		return "", false
+	}
+	return q.String(), true
+}
+
 func (f *ArxivFetcher) Fetch(ctx context.Context, topic string, maxResults int) ([]Paper, error) {
 	var papers []Paper
-	
+
 	err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
 		var err error
 		papers, err = f.fetchInternal(ctx, topic, maxResults)
 		return err
 	})
-	
-	return papers, err
+	if err != nil {
+		return nil, err
+	}
+
+	return tagTopic(papers, topic), nil
 }
 
 func (f *ArxivFetcher) fetchInternal(ctx context.Context, topic string, maxResults int) ([]Paper, error) {
+	return f.queryPage(ctx, f.topicQuery(topic), "descending", 0, maxResults)
+}
+
+// topicQuery returns the search_query of a single topic.
+func (f *ArxivFetcher) topicQuery(topic string) string {
+	if q, ok := f.searchQuery(topic); ok {
+		return q
+	}
+	return fmt.Sprintf("all:%s", topic)
+}
+
+// queryPage requests one page of results for searchQuery, sorted by
+// submission date in sortOrder ("ascending" or "descending").
+func (f *ArxivFetcher) queryPage(ctx context.Context, searchQuery, sortOrder string, start, maxResults int) ([]Paper, error) {
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	query := url.Values{}
-	query.Set("search_query", fmt.Sprintf("all:%s", topic))
-	query.Set("start", "0")
+	query.Set("search_query", searchQuery)
+	query.Set("start", fmt.Sprintf("%d", start))
 	query.Set("max_results", fmt.Sprintf("%d", maxResults))
 	query.Set("sortBy", "submittedDate")
-	query.Set("sortOrder", "descending")
+	query.Set("sortOrder", sortOrder)
 
 	reqURL := fmt.Sprintf("%s?%s", f.baseURL, query.Encode())
 
@@ -115,41 +265,16 @@ func (f *ArxivFetcher) fetchInternal(ctx context.Context, topic string, maxResul
 
 	papers := make([]Paper, 0, len(feed.Entries))
 	for _, entry := range feed.Entries {
-		published, _ := time.Parse(time.RFC3339, entry.Published)
-
-		authors := make([]string, len(entry.Authors))
-		for i, a := range entry.Authors {
-			authors[i] = strings.TrimSpace(a.Name)
-		}
-
-		var paperURL string
-		for _, link := range entry.Links {
-			if link.Rel == "alternate" || (link.Type == "text/html" && paperURL == "") {
-				paperURL = link.Href
-			}
-		}
-		if paperURL == "" && len(entry.Links) > 0 {
-			paperURL = entry.Links[0].Href
-		}
-
-		var category string
-		if len(entry.Category) > 0 {
-			category = entry.Category[0].Term
-		}
-
-		papers = append(papers, Paper{
-			Title:     strings.TrimSpace(entry.Title),
-			Authors:   authors,
-			Abstract:  strings.TrimSpace(entry.Summary),
-			URL:       paperURL,
-			Published: published,
-			Category:  category,
-		})
+		papers = append(papers, entry.toPaper())
 	}
 
 	return papers, nil
 }
 
+// FetchMultiple queries each topic separately and concurrently, so that every
+// paper records the topics it matched. The merged results keep at least
+// minPerTopic papers of each topic before the remaining places go to the
+// newest papers overall.
 func (f *ArxivFetcher) FetchMultiple(ctx context.Context, topics []string, maxResults int) ([]Paper, error) {
 	if len(topics) == 0 {
 		return []Paper{}, nil
@@ -159,109 +284,115 @@ func (f *ArxivFetcher) FetchMultiple(ctx context.Context, topics []string, maxRe
 		return f.Fetch(ctx, topics[0], maxResults)
 	}
 
-	var papers []Paper
-	
-	err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
-		var err error
-		papers, err = f.fetchMultipleInternal(ctx, topics, maxResults)
-		return err
+	all, err := f.fetchEach(ctx, topics, func(ctx context.Context, topic string) ([]Paper, error) {
+		return f.Fetch(ctx, topic, maxResults)
 	})
-	
-	return papers, err
-}
-
-func (f *ArxivFetcher) fetchMultipleInternal(ctx context.Context, topics []string, maxResults int) ([]Paper, error) {
-	// For multiple topics, we'll construct a single query that includes all topics
-	// using OR logic, then fetch more results to account for the combined search
-	query := url.Values{}
-	
-	// Create a combined search query: (all:topic1) OR (all:topic2) OR ...
-	var searchQueries []string
-	for _, topic := range topics {
-		searchQueries = append(searchQueries, fmt.Sprintf("all:\"%s\"", strings.ReplaceAll(topic, "\"", "")))
+	if err != nil {
+		return nil, err
 	}
-	combinedQuery := strings.Join(searchQueries, " OR ")
-	
-	query.Set("search_query", combinedQuery)
-	query.Set("start", "0")
-	// Fetch more results since we're combining multiple topics
-	query.Set("max_results", fmt.Sprintf("%d", maxResults*2))
-	query.Set("sortBy", "submittedDate")
-	query.Set("sortOrder", "descending")
 
-	reqURL := fmt.Sprintf("%s?%s", f.baseURL, query.Encode())
+	return selectByTopic(all, topics, maxResults, f.minPerTopic), nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("arxiv: failed to create request: %w", err)
+// fetchEach runs fetch for every topic concurrently and returns all papers.
+// Requests are still spaced out by the shared rate limiter.
+func (f *ArxivFetcher) fetchEach(ctx context.Context, topics []string, fetch func(ctx context.Context, topic string) ([]Paper, error)) ([]Paper, error) {
+	results := make([][]Paper, len(topics))
+	errs := make([]error, len(topics))
+
+	var wg sync.WaitGroup
+	for i, topic := range topics {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = fetch(ctx, topic)
+		}()
 	}
+	wg.Wait()
 
-	resp, err := f.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("arxiv: request failed: %w", err)
+	var all []Paper
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("arxiv: topic %q: %w", topics[i], err)
+		}
+		all = append(all, results[i]...)
 	}
-	defer resp.Body.Close()
+	return all, nil
+}
 
-	// Check if status code is retryable for non-success responses
-	if resp.StatusCode != http.StatusOK {
-		if !retry.HTTPStatusRetryable(resp.StatusCode) {
-			return nil, fmt.Errorf("arxiv: unexpected status %d", resp.StatusCode)
-		}
-		return nil, fmt.Errorf("arxiv: unexpected status %d", resp.StatusCode)
+// FetchSince pages through the papers of each topic submitted after since,
+// oldest first, so that a topic stopped early has still covered the time
+// right after since. Pages are spaced out by the rate limiter, and each topic
+// stops early with a warning once maxTotal papers were collected; until is
+// then the time up to which every paper of every topic was returned.
+func (f *ArxivFetcher) FetchSince(ctx context.Context, topics []string, since time.Time) ([]Paper, time.Time, error) {
+	if len(topics) == 0 {
+		return []Paper{}, time.Time{}, nil
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	var (
+		mu    sync.Mutex
+		until time.Time
+	)
+	all, err := f.fetchEach(ctx, topics, func(ctx context.Context, topic string) ([]Paper, error) {
+		papers, topicUntil, err := f.fetchTopicSince(ctx, topic, since)
+		if !topicUntil.IsZero() {
+			mu.Lock()
+			if until.IsZero() || topicUntil.Before(until) {
+				until = topicUntil
+			}
+			mu.Unlock()
+		}
+		return papers, err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("arxiv: failed to read response: %w", err)
+		return nil, time.Time{}, err
 	}
 
-	var feed arxivFeed
-	if err := xml.Unmarshal(body, &feed); err != nil {
-		return nil, fmt.Errorf("arxiv: failed to parse XML: %w", err)
-	}
+	return mergePapers(all, len(all)), until, nil
+}
 
-	papers := make([]Paper, 0, len(feed.Entries))
-	for _, entry := range feed.Entries {
-		published, _ := time.Parse(time.RFC3339, entry.Published)
+// fetchTopicSince returns the papers of topic submitted after since. When
+// maxTotal stops it early, it also returns the time up to which every paper
+// was collected.
+func (f *ArxivFetcher) fetchTopicSince(ctx context.Context, topic string, since time.Time) ([]Paper, time.Time, error) {
+	searchQuery := f.topicQuery(topic)
+	if !since.IsZero() {
+		searchQuery = fmt.Sprintf("(%s) AND submittedDate:[%s TO %s]", searchQuery,
+			since.UTC().Format(arxivDateLayout), time.Now().UTC().Format(arxivDateLayout))
+	}
 
-		authors := make([]string, len(entry.Authors))
-		for i, a := range entry.Authors {
-			authors[i] = strings.TrimSpace(a.Name)
+	var papers []Paper
+	for start := 0; start < f.maxTotal; start += f.pageSize {
+		n := min(f.pageSize, f.maxTotal-start)
+		var page []Paper
+		err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
+			var err error
+			page, err = f.queryPage(ctx, searchQuery, "ascending", start, n)
+			return err
+		})
+		if err != nil {
+			return nil, time.Time{}, err
 		}
 
-		var paperURL string
-		for _, link := range entry.Links {
-			if link.Rel == "alternate" || (link.Type == "text/html" && paperURL == "") {
-				paperURL = link.Href
+		for _, p := range page {
+			// submittedDate ranges only have minute precision.
+			if p.Published.After(since) {
+				papers = append(papers, p)
 			}
 		}
-		if paperURL == "" && len(entry.Links) > 0 {
-			paperURL = entry.Links[0].Href
+		if len(page) < n {
+			return tagTopic(papers, topic), time.Time{}, nil
 		}
-
-		var category string
-		if len(entry.Category) > 0 {
-			category = entry.Category[0].Term
-		}
-
-		papers = append(papers, Paper{
-			Title:     strings.TrimSpace(entry.Title),
-			Authors:   authors,
-			Abstract:  strings.TrimSpace(entry.Summary),
-			URL:       paperURL,
-			Published: published,
-			Category:  category,
-		})
 	}
 
-	// Sort papers by publication date (newest first) and limit to maxResults
-	sort.Slice(papers, func(i, j int) bool {
-		return papers[i].Published.After(papers[j].Published)
-	})
-
-	if len(papers) > maxResults {
-		papers = papers[:maxResults]
+	// Papers submitted at the same time as the last one collected may have
+	// been cut off, so only the time just before it is fully covered.
+	until := since
+	if len(papers) > 0 {
+		until = papers[len(papers)-1].Published.Add(-time.Nanosecond)
 	}
-
-	return papers, nil
-}
\ No newline at end of file
+	log.Printf("WARNING: arxiv: stopped after %d papers for %q at %s; the next run continues from there",
+		len(papers), topic, until.Format(time.RFC3339))
+	return tagTopic(papers, topic), until, nil
+}
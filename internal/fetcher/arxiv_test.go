@@ -2,14 +2,21 @@ package fetcher
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 const sampleAtomFeed = `<?xml version="1.0" encoding="UTF-8"?>
 <feed xmlns="http://www.w3.org/2005/Atom">
   <entry>
+    <id>http://arxiv.org/abs/1234.5678v2</id>
     <title>  Sample Paper Title  </title>
     <summary>  This is the abstract of the paper.  </summary>
     <author><name> Alice </name></author>
@@ -71,6 +78,9 @@ func TestFetchParsesAtomFeed(t *testing.T) {
 	if p.URL != "http://arxiv.org/abs/1234.5678" {
 		t.Errorf("Expected alternate link URL, got %q", p.URL)
 	}
+	if p.ID != "1234.5678" {
+		t.Errorf("Expected versionless arXiv ID '1234.5678', got %q", p.ID)
+	}
 	if p.Category != "cs.AI" {
 		t.Errorf("Expected category 'cs.AI', got %q", p.Category)
 	}
@@ -119,9 +129,12 @@ func TestFetchQueryParameters(t *testing.T) {
 }
 
 func TestFetchMultipleTopics(t *testing.T) {
-	var receivedQuery string
+	var mu sync.Mutex
+	var receivedQueries []string
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		receivedQuery = r.URL.RawQuery
+		mu.Lock()
+		receivedQueries = append(receivedQueries, r.URL.Query().Get("search_query"))
+		mu.Unlock()
 		w.Header().Set("Content-Type", "application/xml")
 		w.Write([]byte(sampleAtomFeed))
 	}))
@@ -138,18 +151,54 @@ func TestFetchMultipleTopics(t *testing.T) {
 		t.Fatalf("FetchMultiple returned error: %v", err)
 	}
 
+	// Both topics return the same papers, which are merged by arXiv ID
 	if len(papers) != 2 {
 		t.Fatalf("Expected 2 papers, got %d", len(papers))
 	}
 
-	// Check that the query contains both topics
-	if !contains(receivedQuery, "quantum+computing") || !contains(receivedQuery, "artificial+intelligence") {
-		t.Errorf("Expected query to contain both topics, got %q", receivedQuery)
+	// Check that each topic is queried on its own
+	sort.Strings(receivedQueries)
+	want := []string{"all:artificial intelligence", "all:quantum computing"}
+	if !slices.Equal(receivedQueries, want) {
+		t.Errorf("Expected one query per topic %q, got %q", want, receivedQueries)
 	}
-	
-	// Check that OR logic is used
-	if !contains(receivedQuery, "OR") {
-		t.Errorf("Expected query to use OR logic, got %q", receivedQuery)
+
+	// Check that the merged papers record both topics
+	for _, p := range papers {
+		if len(p.Topics) != 2 {
+			t.Errorf("Expected paper %s to record both topics, got %v", p.ID, p.Topics)
+		}
+	}
+}
+
+func TestFetchMultipleKeepsMinimumPerTopic(t *testing.T) {
+	feeds := map[string]string{
+		"all:busy":  arxivPage("2025-01-20", "2025-01-19", "2025-01-18", "2025-01-17"),
+		"all:quiet": arxivPage("2025-01-10", "2025-01-09"),
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(feeds[r.URL.Query().Get("search_query")]))
+	}))
+	defer ts.Close()
+
+	f := &ArxivFetcher{client: ts.Client(), baseURL: ts.URL}
+	f.SetMinPerTopic(1)
+
+	papers, err := f.FetchMultiple(context.Background(), []string{"busy", "quiet"}, 3)
+	if err != nil {
+		t.Fatalf("FetchMultiple returned error: %v", err)
+	}
+
+	var ids []string
+	for _, p := range papers {
+		ids = append(ids, p.ID)
+	}
+	want := []string{"2025-01-20", "2025-01-19", "2025-01-10"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("Expected %v, got %v", want, ids)
+	}
+	if !slices.Equal(papers[2].Topics, []string{"quiet"}) {
+		t.Errorf("Expected the last paper to be attributed to quiet, got %v", papers[2].Topics)
 	}
 }
 
@@ -263,4 +312,167 @@ func searchString(s, substr string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+func TestParseArxivID(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"http://arxiv.org/abs/2401.12345v1", "2401.12345"},
+		{"http://arxiv.org/abs/2401.12345", "2401.12345"},
+		{"http://arxiv.org/abs/hep-th/9901001v3", "hep-th/9901001"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseArxivID(tt.raw); got != tt.want {
+			t.Errorf("parseArxivID(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+const richAtomFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/2501.01234v3</id>
+    <updated>2025-02-01T12:00:00Z</updated>
+    <published>2025-01-02T09:00:00Z</published>
+    <title>Rich Metadata Paper</title>
+    <summary>Abstract.</summary>
+    <author><name>Alice</name></author>
+    <arxiv:doi>10.1000/example.123</arxiv:doi>
+    <link title="doi" href="http://dx.doi.org/10.1000/example.123" rel="related"/>
+    <arxiv:comment>Accepted at CVPR 2025;
+      12 pages, 5 figures</arxiv:comment>
+    <arxiv:journal_ref>Proc. CVPR 2025, pp. 1-12</arxiv:journal_ref>
+    <link href="http://arxiv.org/abs/2501.01234v3" rel="alternate" type="text/html"/>
+    <link title="pdf" href="http://arxiv.org/pdf/2501.01234v3" rel="related" type="application/pdf"/>
+    <arxiv:primary_category term="cs.CV" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.CV" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+
+func TestFetchParsesArxivMetadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(richAtomFeed))
+	}))
+	defer ts.Close()
+
+	f := &ArxivFetcher{client: ts.Client(), baseURL: ts.URL}
+
+	papers, err := f.Fetch(context.Background(), "vision", 10)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if len(papers) != 1 {
+		t.Fatalf("Expected 1 paper, got %d", len(papers))
+	}
+
+	p := papers[0]
+	if p.ID != "2501.01234" || p.Version != 3 {
+		t.Errorf("Expected ID 2501.01234 version 3, got %q version %d", p.ID, p.Version)
+	}
+	if p.Updated.Format("2006-01-02") != "2025-02-01" {
+		t.Errorf("Unexpected updated date: %v", p.Updated)
+	}
+	if p.Category != "cs.CV" {
+		t.Errorf("Expected primary category cs.CV, got %q", p.Category)
+	}
+	if len(p.Categories) != 2 || p.Categories[0] != "cs.LG" {
+		t.Errorf("Expected every category, got %v", p.Categories)
+	}
+	if p.DOI != "10.1000/example.123" {
+		t.Errorf("Unexpected DOI: %q", p.DOI)
+	}
+	if p.JournalRef != "Proc. CVPR 2025, pp. 1-12" {
+		t.Errorf("Unexpected journal ref: %q", p.JournalRef)
+	}
+	if p.Comment != "Accepted at CVPR 2025; 12 pages, 5 figures" {
+		t.Errorf("Expected comment with collapsed whitespace, got %q", p.Comment)
+	}
+	if p.PDFURL != "http://arxiv.org/pdf/2501.01234v3" {
+		t.Errorf("Unexpected PDF URL: %q", p.PDFURL)
+	}
+}
+
+// arxivPage renders a feed with one entry per published date.
+func arxivPage(dates ...string) string {
+	var sb strings.Builder
+	sb.WriteString(`<feed xmlns="http://www.w3.org/2005/Atom">`)
+	for _, d := range dates {
+		fmt.Fprintf(&sb, `<entry><id>http://arxiv.org/abs/%s</id><title>Paper %s</title><published>%sT00:00:00Z</published></entry>`, d, d, d)
+	}
+	sb.WriteString(`</feed>`)
+	return sb.String()
+}
+
+func TestFetchSincePagesFromHighWaterMark(t *testing.T) {
+	pages := map[string]string{
+		"0": arxivPage("2025-01-16", "2025-01-17"),
+		"2": arxivPage("2025-01-18", "2025-01-19"),
+		"4": arxivPage("2025-01-20"),
+	}
+	var starts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		starts = append(starts, q.Get("start"))
+		if q.Get("max_results") != "2" || q.Get("sortOrder") != "ascending" {
+			t.Errorf("Expected ascending pages of 2, got %q", r.URL.RawQuery)
+		}
+		if !strings.HasPrefix(q.Get("search_query"), "(all:ml) AND submittedDate:[202501160000 TO ") {
+			t.Errorf("Expected the query bounded by the mark, got %q", q.Get("search_query"))
+		}
+		w.Write([]byte(pages[q.Get("start")]))
+	}))
+	defer ts.Close()
+
+	f := &ArxivFetcher{client: ts.Client(), baseURL: ts.URL, pageSize: 2, maxTotal: 100}
+
+	since := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)
+	papers, until, err := f.FetchSince(context.Background(), []string{"ml"}, since)
+	if err != nil {
+		t.Fatalf("FetchSince returned error: %v", err)
+	}
+
+	if len(starts) != 3 || starts[2] != "4" {
+		t.Errorf("Expected pages at 0, 2 and 4, got %v", starts)
+	}
+	if len(papers) != 4 {
+		t.Fatalf("Expected the 4 papers after the mark, got %d", len(papers))
+	}
+	if papers[0].ID != "2025-01-20" || papers[3].ID != "2025-01-17" {
+		t.Errorf("Expected papers newest first from 2025-01-20 to 2025-01-17, got %q to %q", papers[0].ID, papers[3].ID)
+	}
+	if !until.IsZero() {
+		t.Errorf("Expected no until time for a complete fetch, got %v", until)
+	}
+}
+
+func TestFetchSinceStopsAtMaxTotal(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("start") == "0" {
+			w.Write([]byte(arxivPage("2025-01-17", "2025-01-18")))
+			return
+		}
+		w.Write([]byte(arxivPage("2025-01-19", "2025-01-20")))
+	}))
+	defer ts.Close()
+
+	f := &ArxivFetcher{client: ts.Client(), baseURL: ts.URL, pageSize: 2, maxTotal: 3}
+
+	since := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)
+	papers, until, err := f.FetchSince(context.Background(), []string{"ml"}, since)
+	if err != nil {
+		t.Fatalf("FetchSince returned error: %v", err)
+	}
+	if requests != 2 || len(papers) != 4 {
+		t.Errorf("Expected 2 requests bounded by max total, got %d requests and %d papers", requests, len(papers))
+	}
+	if want := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond); !until.Equal(want) {
+		t.Errorf("Expected until just before the last paper collected, got %v", until)
+	}
+}
@@ -0,0 +1,96 @@
+package fetcher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// arxivFieldPrefixes are the field prefixes understood by the arXiv search API.
+var arxivFieldPrefixes = []string{"ti", "au", "abs", "co", "jr", "cat", "rn", "id", "all"}
+
+// arxivDateLayout is the timestamp format of submittedDate ranges.
+const arxivDateLayout = "200601021504"
+
+// ArxivQuery is a structured arXiv search. Terms may carry a field prefix
+// such as "ti:", "abs:", "au:" or "cat:"; terms without one search all fields.
+type ArxivQuery struct {
+	All []string // Every term must match (AND)
+	Any []string // At least one term must match (OR)
+	Not []string // No term may match (ANDNOT)
+
+	// Topic is searched in all fields when neither All nor Any is given, so
+	// that a query of only Not terms or a date range still has a subject.
+	Topic string
+
+	// Optional submittedDate range; a zero bound is left open.
+	SubmittedFrom time.Time
+	SubmittedTo   time.Time
+
+	Raw string // A complete search_query, used verbatim when set
+}
+
+// String compiles the query into arXiv search_query syntax, e.g.
+// `ti:"diffusion" AND (cat:cs.CV OR cat:cs.LG) ANDNOT ti:survey`.
+func (q ArxivQuery) String() string {
+	if q.Raw != "" {
+		return q.Raw
+	}
+
+	var clauses []string
+	if len(q.All) == 0 && len(q.Any) == 0 && q.Topic != "" {
+		clauses = append(clauses, arxivTerm(q.Topic))
+	}
+	for _, t := range q.All {
+		clauses = append(clauses, arxivTerm(t))
+	}
+	if len(q.Any) > 0 {
+		terms := make([]string, len(q.Any))
+		for i, t := range q.Any {
+			terms[i] = arxivTerm(t)
+		}
+		if len(terms) == 1 {
+			clauses = append(clauses, terms[0])
+		} else {
+			clauses = append(clauses, "("+strings.Join(terms, " OR ")+")")
+		}
+	}
+	if !q.SubmittedFrom.IsZero() || !q.SubmittedTo.IsZero() {
+		from, to := q.SubmittedFrom, q.SubmittedTo
+		if to.IsZero() {
+			to = time.Now().UTC()
+		}
+		clauses = append(clauses, fmt.Sprintf("submittedDate:[%s TO %s]", from.Format(arxivDateLayout), to.Format(arxivDateLayout)))
+	}
+
+	query := strings.Join(clauses, " AND ")
+	for _, t := range q.Not {
+		query += " ANDNOT " + arxivTerm(t)
+	}
+	return query
+}
+
+// arxivTerm formats a single term, quoting multi-word values and defaulting
+// to the all: field when no known prefix is given.
+func arxivTerm(term string) string {
+	term = strings.TrimSpace(term)
+	field, value := "all", term
+	if prefix, rest, ok := strings.Cut(term, ":"); ok && isArxivField(prefix) {
+		field, value = prefix, strings.TrimSpace(rest)
+	}
+
+	value = strings.ReplaceAll(value, `"`, "")
+	if strings.ContainsAny(value, " \t") {
+		value = `"` + value + `"`
+	}
+	return field + ":" + value
+}
+
+func isArxivField(prefix string) bool {
+	for _, f := range arxivFieldPrefixes {
+		if prefix == f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestArxivQueryString(t *testing.T) {
+	tests := []struct {
+		name  string
+		query ArxivQuery
+		want  string
+	}{
+		{
+			"all terms",
+			ArxivQuery{All: []string{"ti:diffusion model", "cat:cs.CV"}},
+			`ti:"diffusion model" AND cat:cs.CV`,
+		},
+		{
+			"any and not",
+			ArxivQuery{All: []string{"quantum computing"}, Any: []string{"cat:quant-ph", "cat:cs.ET"}, Not: []string{"ti:survey", "au:Doe"}},
+			`all:"quantum computing" AND (cat:quant-ph OR cat:cs.ET) ANDNOT ti:survey ANDNOT au:Doe`,
+		},
+		{
+			"single any term",
+			ArxivQuery{Any: []string{"abs:retrieval"}},
+			`abs:retrieval`,
+		},
+		{
+			"unknown prefix searches all fields",
+			ArxivQuery{All: []string{`note: "a b"`}},
+			`all:"note: a b"`,
+		},
+		{
+			"submitted date range",
+			ArxivQuery{
+				All:           []string{"cat:cs.LG"},
+				SubmittedFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				SubmittedTo:   time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC),
+			},
+			`cat:cs.LG AND submittedDate:[202501010000 TO 202501312359]`,
+		},
+		{
+			"only not terms search the topic",
+			ArxivQuery{Topic: "graph neural networks", Not: []string{"ti:survey"}},
+			`all:"graph neural networks" ANDNOT ti:survey`,
+		},
+		{
+			"only a date range searches the topic",
+			ArxivQuery{
+				Topic:         "robotics",
+				SubmittedFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				SubmittedTo:   time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC),
+			},
+			`all:robotics AND submittedDate:[202501010000 TO 202501312359]`,
+		},
+		{
+			"topic is not added to explicit terms",
+			ArxivQuery{Topic: "robotics", Any: []string{"cat:cs.RO"}},
+			`cat:cs.RO`,
+		},
+		{
+			"raw query",
+			ArxivQuery{Raw: "ti:foo OR ti:bar", All: []string{"ignored"}},
+			`ti:foo OR ti:bar`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchUsesStructuredQueries(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query().Get("search_query"))
+		mu.Unlock()
+		w.Write([]byte(sampleAtomFeed))
+	}))
+	defer ts.Close()
+
+	f := &ArxivFetcher{client: ts.Client(), baseURL: ts.URL}
+	f.SetQueries(map[string]ArxivQuery{
+		"LLM agents": {All: []string{"ti:agent"}, Any: []string{"cat:cs.AI", "cat:cs.CL"}},
+	})
+
+	if _, err := f.Fetch(context.Background(), "LLM agents", 5); err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if _, err := f.FetchMultiple(context.Background(), []string{"LLM agents", "robotics"}, 5); err != nil {
+		t.Fatalf("FetchMultiple returned error: %v", err)
+	}
+
+	// Each topic of FetchMultiple is queried on its own, in any order.
+	sort.Strings(queries[1:])
+	want := []string{
+		`ti:agent AND (cat:cs.AI OR cat:cs.CL)`,
+		`all:robotics`,
+		`ti:agent AND (cat:cs.AI OR cat:cs.CL)`,
+	}
+	if !slices.Equal(queries, want) {
+		t.Errorf("Unexpected search queries:\n got %q\nwant %q", queries, want)
+	}
+}
@@ -0,0 +1,194 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/retry"
+)
+
+// bioRxiv/medRxiv "details" API response structures
+
+type biorxivResponse struct {
+	Messages   []biorxivMessage `json:"messages"`
+	Collection []biorxivPaper   `json:"collection"`
+}
+
+type biorxivMessage struct {
+	Status string      `json:"status"`
+	Cursor json.Number `json:"cursor"`
+	Count  json.Number `json:"count"`
+	Total  json.Number `json:"total"`
+}
+
+type biorxivPaper struct {
+	DOI      string `json:"doi"`
+	Title    string `json:"title"`
+	Authors  string `json:"authors"`
+	Date     string `json:"date"`
+	Version  string `json:"version"`
+	Category string `json:"category"`
+	Abstract string `json:"abstract"`
+	Server   string `json:"server"`
+}
+
+// BiorxivFetcher fetches recent preprints from bioRxiv or medRxiv. Their API
+// lists papers by date interval rather than by keyword, so the whole interval
+// is paged through and topics are matched locally against the title, abstract
+// and category.
+type BiorxivFetcher struct {
+	client      *http.Client
+	baseURL     string
+	server      string // "biorxiv" or "medrxiv"
+	days        int    // Size of the date interval, ending today
+	maxPages    int    // Safety bound on the number of pages per run
+	limiter     *rateLimiter
+	retryConfig retry.Config
+}
+
+// NewBiorxivFetcher creates a fetcher for server ("biorxiv" or "medrxiv")
+// covering the last days days.
+func NewBiorxivFetcher(server string, days int) *BiorxivFetcher {
+	return &BiorxivFetcher{
+		client:   &http.Client{Timeout: 60 * time.Second},
+		baseURL:  "https://api.biorxiv.org",
+		server:   server,
+		days:     days,
+		maxPages: 100,
+		limiter:  newRateLimiter(500 * time.Millisecond),
+		retryConfig: retry.Config{
+			MaxRetries: 3,
+			BaseDelay:  2 * time.Second,
+		},
+	}
+}
+
+func (f *BiorxivFetcher) Fetch(ctx context.Context, topic string, maxResults int) ([]Paper, error) {
+	return f.FetchMultiple(ctx, []string{topic}, maxResults)
+}
+
+func (f *BiorxivFetcher) FetchMultiple(ctx context.Context, topics []string, maxResults int) ([]Paper, error) {
+	if len(topics) == 0 {
+		return []Paper{}, nil
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -f.days)
+
+	var matched []Paper
+	cursor := 0
+	for page := 0; page < f.maxPages; page++ {
+		var resp *biorxivResponse
+		err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
+			var err error
+			resp, err = f.fetchPage(ctx, from, to, cursor)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bp := range resp.Collection {
+			text := strings.Join([]string{bp.Title, bp.Abstract, bp.Category}, " ")
+			if m := matchingTopics(text, topics); len(m) > 0 {
+				p := bp.toPaper(f.server)
+				p.Topics = m
+				matched = append(matched, p)
+			}
+		}
+
+		cursor += len(resp.Collection)
+		if len(resp.Collection) == 0 || len(resp.Messages) == 0 {
+			break
+		}
+		if total, err := resp.Messages[0].Total.Int64(); err != nil || int64(cursor) >= total {
+			break
+		}
+	}
+
+	// The API lists oldest first; reverse so the newest version of a paper
+	// wins when versions are merged.
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	return mergePapers(matched, maxResults), nil
+}
+
+func (f *BiorxivFetcher) fetchPage(ctx context.Context, from, to time.Time, cursor int) (*biorxivResponse, error) {
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/details/%s/%s/%s/%d/json",
+		f.baseURL, f.server, from.Format("2006-01-02"), to.Format("2006-01-02"), cursor)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create request: %w", f.server, err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: request failed: %w", f.server, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %d", f.server, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read response: %w", f.server, err)
+	}
+
+	var page biorxivResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse JSON: %w", f.server, err)
+	}
+	return &page, nil
+}
+
+// toPaper converts a preprint record into a Paper linked through its DOI.
+func (bp biorxivPaper) toPaper(server string) Paper {
+	published, _ := time.Parse("2006-01-02", bp.Date)
+
+	var authors []string
+	for _, a := range strings.Split(bp.Authors, ";") {
+		if a = strings.TrimSpace(a); a != "" {
+			authors = append(authors, a)
+		}
+	}
+
+	var categories []string
+	if bp.Category != "" {
+		categories = []string{bp.Category}
+	}
+
+	version, _ := strconv.Atoi(bp.Version)
+
+	var pdfURL string
+	if version > 0 {
+		pdfURL = fmt.Sprintf("https://www.%s.org/content/%sv%d.full.pdf", server, bp.DOI, version)
+	}
+
+	return Paper{
+		ID:         "doi:" + bp.DOI,
+		Title:      strings.TrimSpace(bp.Title),
+		Authors:    authors,
+		Abstract:   strings.TrimSpace(bp.Abstract),
+		URL:        "https://doi.org/" + bp.DOI,
+		Published:  published,
+		Category:   bp.Category,
+		Categories: categories,
+		Version:    version,
+		DOI:        bp.DOI,
+		PDFURL:     pdfURL,
+	}
+}
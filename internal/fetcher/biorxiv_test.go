@@ -0,0 +1,148 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const biorxivPageOne = `{
+  "messages": [{"status": "ok", "cursor": 0, "count": 2, "total": "3"}],
+  "collection": [
+    {
+      "doi": "10.1101/2025.01.10.000001",
+      "title": "Single-cell atlas of the mouse retina",
+      "authors": "Smith, J.; Doe, A.; ",
+      "date": "2025-01-10",
+      "version": "1",
+      "category": "neuroscience",
+      "abstract": "We profile retinal cells.",
+      "server": "bioRxiv"
+    },
+    {
+      "doi": "10.1101/2025.01.11.000002",
+      "title": "Protein structure prediction with language models",
+      "authors": "Lee, K.",
+      "date": "2025-01-11",
+      "version": "1",
+      "category": "bioinformatics",
+      "abstract": "A new model for folding.",
+      "server": "bioRxiv"
+    }
+  ]
+}`
+
+const biorxivPageTwo = `{
+  "messages": [{"status": "ok", "cursor": 2, "count": 1, "total": 3}],
+  "collection": [
+    {
+      "doi": "10.1101/2025.01.10.000001",
+      "title": "Single-cell atlas of the mouse retina",
+      "authors": "Smith, J.; Doe, A.; Roe, B.",
+      "date": "2025-01-12",
+      "version": "2",
+      "category": "neuroscience",
+      "abstract": "We profile retinal cells in depth.",
+      "server": "bioRxiv"
+    }
+  ]
+}`
+
+func TestBiorxivFetchPagesAndMatchesLocally(t *testing.T) {
+	var cursors []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 6 || parts[0] != "details" || parts[1] != "biorxiv" || parts[5] != "json" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+			return
+		}
+		cursors = append(cursors, parts[4])
+		if parts[4] == "0" {
+			fmt.Fprint(w, biorxivPageOne)
+		} else {
+			fmt.Fprint(w, biorxivPageTwo)
+		}
+	}))
+	defer ts.Close()
+
+	f := &BiorxivFetcher{client: ts.Client(), baseURL: ts.URL, server: "biorxiv", days: 2, maxPages: 10}
+
+	papers, err := f.Fetch(context.Background(), "Retina single-cell", 10)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+
+	if len(cursors) != 2 || cursors[1] != "2" {
+		t.Errorf("Expected two pages at cursors 0 and 2, got %v", cursors)
+	}
+	if len(papers) != 1 {
+		t.Fatalf("Expected versions of one matching paper to be merged, got %d", len(papers))
+	}
+
+	p := papers[0]
+	if p.ID != "doi:10.1101/2025.01.10.000001" {
+		t.Errorf("Unexpected ID: %q", p.ID)
+	}
+	if p.URL != "https://doi.org/10.1101/2025.01.10.000001" {
+		t.Errorf("Expected DOI-based URL, got %q", p.URL)
+	}
+	if p.Published.Format("2006-01-02") != "2025-01-12" || len(p.Authors) != 3 {
+		t.Errorf("Expected the newest version to win, got %v / %v", p.Published, p.Authors)
+	}
+	if p.Category != "neuroscience" {
+		t.Errorf("Unexpected category: %q", p.Category)
+	}
+}
+
+func TestBiorxivFetchMultipleMatchesAnyTopic(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, biorxivPageOne)
+	}))
+	defer ts.Close()
+
+	f := &BiorxivFetcher{client: ts.Client(), baseURL: ts.URL, server: "medrxiv", days: 2, maxPages: 1}
+
+	papers, err := f.FetchMultiple(context.Background(), []string{"retina", "bioinformatics", "cardiology"}, 10)
+	if err != nil {
+		t.Fatalf("FetchMultiple returned error: %v", err)
+	}
+	if len(papers) != 2 {
+		t.Errorf("Expected 2 matching papers, got %d", len(papers))
+	}
+}
+
+func TestBiorxivFetchBadStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	f := &BiorxivFetcher{client: ts.Client(), baseURL: ts.URL, server: "biorxiv", days: 2, maxPages: 1}
+
+	_, err := f.Fetch(context.Background(), "test", 5)
+	if err == nil {
+		t.Fatal("Expected error for 404 status code")
+	}
+	if !contains(err.Error(), "unexpected status 404") {
+		t.Errorf("Expected 'unexpected status 404' error, got: %v", err)
+	}
+}
+
+func TestMatchesTopic(t *testing.T) {
+	tests := []struct {
+		text, topic string
+		want        bool
+	}{
+		{"Protein Structure Prediction", "protein prediction", true},
+		{"Protein Structure Prediction", "protein design", false},
+		{"anything", "  ", false},
+	}
+	for _, tt := range tests {
+		if got := matchesTopic(tt.text, tt.topic); got != tt.want {
+			t.Errorf("matchesTopic(%q, %q) = %v, want %v", tt.text, tt.topic, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/retry"
+)
+
+// RSS 2.0 and Atom document structures. A single struct decodes both: RSS
+// fills Channel, Atom fills Title and Entries.
+
+type feedDocument struct {
+	XMLName xml.Name
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Title   feedText    `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title       feedText `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        string   `xml:"guid"`
+	Description feedText `xml:"description"`
+	Content     feedText `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Author      string   `xml:"author"`
+	Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	PubDate     string   `xml:"pubDate"`
+	DCDate      string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Categories  []string `xml:"category"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     feedText    `xml:"title"`
+	Summary   feedText    `xml:"summary"`
+	Content   feedText    `xml:"content"`
+	Authors   []atomName  `xml:"author"`
+	Links     []arxivLink `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Category  []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+}
+
+type atomName struct {
+	Name string `xml:"name"`
+}
+
+// feedText captures element content that may be plain text, escaped HTML,
+// CDATA or inline XHTML.
+type feedText struct {
+	Type  string `xml:"type,attr"` // Atom only: "text", "html" or "xhtml"
+	Inner string `xml:",innerxml"`
+}
+
+// cdataRegex matches a CDATA section, whose content is taken literally.
+var cdataRegex = regexp.MustCompile(`(?s)<!\[CDATA\[(.*?)\]\]>`)
+
+// htmlTagRegex matches HTML tags, comments and declarations. Unlike
+// xmlTagRegex it leaves alone a "<" that does not open a tag, as browsers
+// do, so that text such as "p < 0.05" survives.
+var htmlTagRegex = regexp.MustCompile(`<(?:/?[A-Za-z][^>]*|![^>]*|\?[^>]*)>`)
+
+// String returns the element text with markup removed and whitespace
+// collapsed. The element is decoded as XML first, dropping inline XHTML tags
+// and unescaping entities outside CDATA sections. Unless the element is Atom
+// text or XHTML, the result is HTML, whose tags are stripped before its
+// entities are unescaped. Nothing is stripped after the last unescape, so an
+// escaped "<" or ">" stays in the text.
+func (t feedText) String() string {
+	var b strings.Builder
+	rest := t.Inner
+	for {
+		loc := cdataRegex.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			break
+		}
+		b.WriteString(html.UnescapeString(xmlTagRegex.ReplaceAllString(rest[:loc[0]], "")))
+		b.WriteString(rest[loc[2]:loc[3]])
+		rest = rest[loc[1]:]
+	}
+	b.WriteString(html.UnescapeString(xmlTagRegex.ReplaceAllString(rest, "")))
+
+	s := b.String()
+	if t.Type != "text" && t.Type != "xhtml" {
+		s = html.UnescapeString(htmlTagRegex.ReplaceAllString(s, ""))
+	}
+	return collapseSpace(s)
+}
+
+// maxFeedAbstract bounds the abstract taken from an entry, since some feeds
+// carry whole blog posts in their content.
+const maxFeedAbstract = 4000
+
+// rssDateLayouts lists the date formats found in the wild in RSS pubDate
+// and Dublin Core date elements.
+var rssDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02",
+}
+
+// FeedFetcher reads entries from a fixed list of RSS 2.0 or Atom feeds, such
+// as journal tables of contents or lab blogs, and keeps those matching the
+// topics. Topics are matched locally against the title, text and categories.
+type FeedFetcher struct {
+	client      *http.Client
+	urls        []string
+	days        int // Only entries published in the last days (0 = no limit)
+	retryConfig retry.Config
+}
+
+// NewFeedFetcher creates a fetcher for the given feed URLs.
+func NewFeedFetcher(urls []string, days int) *FeedFetcher {
+	return &FeedFetcher{
+		client: &http.Client{Timeout: 30 * time.Second},
+		urls:   urls,
+		days:   days,
+		retryConfig: retry.Config{
+			MaxRetries: 3,
+			BaseDelay:  2 * time.Second,
+		},
+	}
+}
+
+func (f *FeedFetcher) Fetch(ctx context.Context, topic string, maxResults int) ([]Paper, error) {
+	return f.FetchMultiple(ctx, []string{topic}, maxResults)
+}
+
+// FetchMultiple reads every feed and keeps the entries matching any topic. A
+// feed that cannot be fetched is skipped with a warning; the call only fails
+// when no feed could be read.
+func (f *FeedFetcher) FetchMultiple(ctx context.Context, topics []string, maxResults int) ([]Paper, error) {
+	var cutoff time.Time
+	if f.days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -f.days)
+	}
+
+	var all []Paper
+	var lastErr error
+	failed := 0
+	for _, feedURL := range f.urls {
+		var papers []Paper
+		err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
+			var err error
+			papers, err = f.fetchFeed(ctx, feedURL)
+			return err
+		})
+		if err != nil {
+			log.Printf("WARNING: skipping feed %s: %v", feedURL, err)
+			lastErr = err
+			failed++
+			continue
+		}
+
+		for _, p := range papers {
+			if !cutoff.IsZero() && !p.Published.IsZero() && p.Published.Before(cutoff) {
+				continue
+			}
+			text := strings.Join(append([]string{p.Title, p.Abstract}, p.Categories...), " ")
+			if m := matchingTopics(text, topics); len(m) > 0 {
+				p.Topics = m
+				all = append(all, p)
+			}
+		}
+	}
+
+	if failed > 0 && failed == len(f.urls) {
+		return nil, lastErr
+	}
+	return mergePapers(all, maxResults), nil
+}
+
+// fetchFeed downloads and parses a single feed.
+func (f *FeedFetcher) fetchFeed(ctx context.Context, feedURL string) ([]Paper, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("feed: failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("feed: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("feed: failed to read response: %w", err)
+	}
+
+	return parseFeed(body)
+}
+
+// parseFeed maps an RSS 2.0 or Atom document to papers.
+func parseFeed(data []byte) ([]Paper, error) {
+	var doc feedDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("feed: failed to parse XML: %w", err)
+	}
+
+	var papers []Paper
+	switch doc.XMLName.Local {
+	case "rss":
+		source := strings.TrimSpace(doc.Channel.Title)
+		for _, item := range doc.Channel.Items {
+			papers = append(papers, item.toPaper(source))
+		}
+	case "feed":
+		source := doc.Title.String()
+		for _, entry := range doc.Entries {
+			papers = append(papers, entry.toPaper(source))
+		}
+	default:
+		return nil, fmt.Errorf("feed: unsupported document root <%s>", doc.XMLName.Local)
+	}
+	return papers, nil
+}
+
+// toPaper converts an RSS item into a Paper. Items without categories are
+// categorized by the channel title.
+func (item rssItem) toPaper(source string) Paper {
+	authors := item.Creators
+	if len(authors) == 0 && item.Author != "" {
+		authors = []string{rssAuthorName(item.Author)}
+	}
+
+	abstract := item.Description.String()
+	if abstract == "" {
+		abstract = item.Content.String()
+	}
+
+	date := item.PubDate
+	if date == "" {
+		date = item.DCDate
+	}
+
+	id := strings.TrimSpace(item.GUID)
+	if id == "" {
+		id = strings.TrimSpace(item.Link)
+	}
+
+	return newFeedPaper(id, item.Title.String(), authors, abstract, strings.TrimSpace(item.Link),
+		parseFeedDate(date), item.Categories, source)
+}
+
+// toPaper converts an Atom entry into a Paper.
+func (entry atomEntry) toPaper(source string) Paper {
+	authors := make([]string, 0, len(entry.Authors))
+	for _, a := range entry.Authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			authors = append(authors, name)
+		}
+	}
+
+	var link string
+	for _, l := range entry.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			link = l.Href
+			break
+		}
+	}
+
+	abstract := entry.Summary.String()
+	if abstract == "" {
+		abstract = entry.Content.String()
+	}
+
+	date := entry.Published
+	if date == "" {
+		date = entry.Updated
+	}
+
+	var categories []string
+	for _, c := range entry.Category {
+		if c.Label != "" {
+			categories = append(categories, c.Label)
+		} else if c.Term != "" {
+			categories = append(categories, c.Term)
+		}
+	}
+
+	id := strings.TrimSpace(entry.ID)
+	if id == "" {
+		id = link
+	}
+
+	return newFeedPaper(id, entry.Title.String(), authors, abstract, link,
+		parseFeedDate(date), categories, source)
+}
+
+func newFeedPaper(id, title string, authors []string, abstract, link string, published time.Time, categories []string, source string) Paper {
+	if r := []rune(abstract); len(r) > maxFeedAbstract {
+		abstract = string(r[:maxFeedAbstract]) + "…"
+	}
+
+	var trimmed []string
+	for _, c := range categories {
+		if c = strings.TrimSpace(c); c != "" {
+			trimmed = append(trimmed, c)
+		}
+	}
+
+	category := source
+	if len(trimmed) > 0 {
+		category = trimmed[0]
+	}
+
+	return Paper{
+		ID:         id,
+		Title:      title,
+		Authors:    authors,
+		Abstract:   abstract,
+		URL:        link,
+		Published:  published,
+		Category:   category,
+		Categories: trimmed,
+	}
+}
+
+// rssAuthorName extracts the name from an RSS author element, which is
+// conventionally "email (Name)".
+func rssAuthorName(author string) string {
+	author = strings.TrimSpace(author)
+	if open := strings.Index(author, "("); open >= 0 && strings.HasSuffix(author, ")") {
+		return strings.TrimSpace(author[open+1 : len(author)-1])
+	}
+	return author
+}
+
+// parseFeedDate parses an RSS or Atom date, returning the zero time when no
+// known layout matches.
+func parseFeedDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range rssDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
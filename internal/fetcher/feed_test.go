@@ -0,0 +1,177 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestParseFeedRSS(t *testing.T) {
+	data, err := os.ReadFile("testdata/journal.rss")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	papers, err := parseFeed(data)
+	if err != nil {
+		t.Fatalf("parseFeed returned error: %v", err)
+	}
+	if len(papers) != 2 {
+		t.Fatalf("Expected 2 papers, got %d", len(papers))
+	}
+
+	p := papers[0]
+	if p.ID != "jcb-101" {
+		t.Errorf("Expected guid as ID, got %q", p.ID)
+	}
+	if p.Abstract != "We apply graph neural networks to protein design & folding." {
+		t.Errorf("Expected markup stripped from description, got %q", p.Abstract)
+	}
+	if len(p.Authors) != 2 || p.Authors[1] != "Bob Jones" {
+		t.Errorf("Expected dc:creator authors, got %v", p.Authors)
+	}
+	if p.Published.Format("2006-01-02") != "2025-01-13" {
+		t.Errorf("Unexpected published date: %v", p.Published)
+	}
+	if p.Category != "Structural Biology" {
+		t.Errorf("Expected item category, got %q", p.Category)
+	}
+
+	p = papers[1]
+	if p.ID != "https://journal.example.org/articles/102" {
+		t.Errorf("Expected link as ID fallback, got %q", p.ID)
+	}
+	if len(p.Authors) != 1 || p.Authors[0] != "The Editors" {
+		t.Errorf("Expected name from RSS author, got %v", p.Authors)
+	}
+	if p.Category != "Journal of Computational Biology" {
+		t.Errorf("Expected channel title as category fallback, got %q", p.Category)
+	}
+}
+
+func TestParseFeedAtom(t *testing.T) {
+	data, err := os.ReadFile("testdata/lab.atom")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	papers, err := parseFeed(data)
+	if err != nil {
+		t.Fatalf("parseFeed returned error: %v", err)
+	}
+	if len(papers) != 2 {
+		t.Fatalf("Expected 2 papers, got %d", len(papers))
+	}
+
+	p := papers[0]
+	if p.ID != "urn:example:lab:post-7" || p.URL != "https://lab.example.org/posts/7" {
+		t.Errorf("Unexpected ID/URL: %q / %q", p.ID, p.URL)
+	}
+	if p.Abstract != "Our folding model now handles protein complexes." {
+		t.Errorf("Expected escaped HTML summary to be stripped, got %q", p.Abstract)
+	}
+	if p.Category != "Protein Folding" || len(p.Authors) != 1 {
+		t.Errorf("Unexpected category/authors: %q / %v", p.Category, p.Authors)
+	}
+
+	p = papers[1]
+	if p.Abstract != "Pictures from the retreat." {
+		t.Errorf("Expected XHTML content as abstract, got %q", p.Abstract)
+	}
+	if p.Published.Format("2006-01-02") != "2025-01-10" {
+		t.Errorf("Expected updated date fallback, got %v", p.Published)
+	}
+	if p.Category != "Example Lab Blog" {
+		t.Errorf("Expected feed title as category fallback, got %q", p.Category)
+	}
+}
+
+func TestParseFeedKeepsComparisons(t *testing.T) {
+	data, err := os.ReadFile("testdata/clinical.rss")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	papers, err := parseFeed(data)
+	if err != nil {
+		t.Fatalf("parseFeed returned error: %v", err)
+	}
+	if len(papers) != 2 {
+		t.Fatalf("Expected 2 papers, got %d", len(papers))
+	}
+	if want := "Mortality fell (p < 0.05) in patients aged > 65 years."; papers[0].Abstract != want {
+		t.Errorf("Expected comparisons kept in plain description, got %q", papers[0].Abstract)
+	}
+	if want := "Systolic pressure <120 mmHg versus <140 mmHg (HR > 1)."; papers[1].Abstract != want {
+		t.Errorf("Expected comparisons kept in HTML description, got %q", papers[1].Abstract)
+	}
+}
+
+func TestParseFeedUnsupported(t *testing.T) {
+	if _, err := parseFeed([]byte(`<html><body/></html>`)); err == nil {
+		t.Fatal("Expected error for non-feed document")
+	}
+}
+
+func TestFeedFetchMultipleFiltersByTopic(t *testing.T) {
+	ts := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer ts.Close()
+
+	f := &FeedFetcher{
+		client: ts.Client(),
+		urls:   []string{ts.URL + "/journal.rss", ts.URL + "/lab.atom", ts.URL + "/missing.xml"},
+	}
+
+	papers, err := f.FetchMultiple(context.Background(), []string{"protein folding", "graph neural"}, 10)
+	if err != nil {
+		t.Fatalf("FetchMultiple returned error: %v", err)
+	}
+	if len(papers) != 2 {
+		t.Fatalf("Expected 2 matching entries, got %d", len(papers))
+	}
+	// Newest first
+	if papers[0].ID != "urn:example:lab:post-7" || papers[1].ID != "jcb-101" {
+		t.Errorf("Unexpected papers: %q, %q", papers[0].ID, papers[1].ID)
+	}
+}
+
+func TestFeedFetchAllFeedsFail(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	f := &FeedFetcher{client: ts.Client(), urls: []string{ts.URL + "/a", ts.URL + "/b"}}
+
+	_, err := f.Fetch(context.Background(), "test", 5)
+	if err == nil {
+		t.Fatal("Expected error when no feed can be read")
+	}
+	if !contains(err.Error(), "unexpected status 404") {
+		t.Errorf("Expected 'unexpected status 404' error, got: %v", err)
+	}
+}
+
+func TestFeedTextString(t *testing.T) {
+	tests := []struct {
+		name string
+		text feedText
+		want string
+	}{
+		{"plain", feedText{Inner: "Protein  design\n folding"}, "Protein design folding"},
+		{"escaped html", feedText{Inner: "&lt;p&gt;Graph &amp;amp; protein&lt;/p&gt;"}, "Graph & protein"},
+		{"cdata", feedText{Inner: "<![CDATA[<p>Graph <b>networks</b></p>]]>"}, "Graph networks"},
+		{"comparisons", feedText{Inner: "p &lt; 0.05 in patients aged &gt; 65 years"}, "p < 0.05 in patients aged > 65 years"},
+		{"escaped comparisons", feedText{Inner: "p &amp;lt; 0.05 in patients aged &amp;gt; 65 years"}, "p < 0.05 in patients aged > 65 years"},
+		{"double-escaped markup", feedText{Inner: "Intro &amp;lt;b&amp;gt;bold&amp;lt;/b&amp;gt; done"}, "Intro <b>bold</b> done"},
+		{"atom text", feedText{Type: "text", Inner: "x &lt;y and z&gt; w"}, "x <y and z> w"},
+		{"xhtml", feedText{Type: "xhtml", Inner: "<div><p>a &amp;lt; b</p></div>"}, "a &lt; b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.text.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,21 +2,174 @@ package fetcher
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Paper represents a single academic publication.
 type Paper struct {
-	Title     string
-	Authors   []string
-	Abstract  string
-	URL       string
-	Published time.Time
-	Category  string
+	ID        string    `json:"id,omitempty"` // Stable source identifier, e.g. the arXiv ID without version
+	Title     string    `json:"title"`
+	Authors   []string  `json:"authors"`
+	Abstract  string    `json:"abstract"`
+	URL       string    `json:"url"`
+	Published time.Time `json:"published"`
+	Category  string    `json:"category"`
+	// Categories lists every subject category or heading of the paper
+	// (arXiv categories, fields of study, MeSH terms, ...).
+	Categories []string `json:"categories,omitempty"`
+	// Topics lists the configured topics the paper was found for.
+	Topics []string `json:"topics,omitempty"`
+
+	// Publication metadata, set when the source provides it.
+	Version    int       `json:"version,omitempty"`     // Latest revision, e.g. 2 for arXiv v2
+	Updated    time.Time `json:"updated,omitzero"`      // When the latest revision was posted
+	DOI        string    `json:"doi,omitempty"`         // e.g. "10.1103/PhysRevD.76.013009"
+	JournalRef string    `json:"journal_ref,omitempty"` // Published venue, e.g. "Phys. Rev. D 76, 013009 (2007)"
+	Comment    string    `json:"comment,omitempty"`     // Author comment, e.g. "Accepted at CVPR 2025; 12 pages"
+	PDFURL     string    `json:"pdf_url,omitempty"`
+
+	// Peer review metadata, set by sources such as OpenReview.
+	Decision     string    `json:"decision,omitempty"`      // e.g. "Accept (poster)"
+	ReviewScores []float64 `json:"review_scores,omitempty"` // One overall rating per review
+	ForumURL     string    `json:"forum_url,omitempty"`     // Discussion page with the reviews
+
+	// Excerpt holds the introduction, method and conclusion text added by the
+	// optional full-text stage. It only feeds the summarizer and is not stored.
+	Excerpt string `json:"-"`
+}
+
+// ReviewInfo summarizes the peer review metadata for display, e.g.
+// "Accept (poster) · scores 6, 8, 5 (avg 6.3)". It is empty when the paper
+// carries no review metadata.
+func (p Paper) ReviewInfo() string {
+	var parts []string
+	if p.Decision != "" {
+		parts = append(parts, p.Decision)
+	}
+	if len(p.ReviewScores) > 0 {
+		scores := make([]string, len(p.ReviewScores))
+		var sum float64
+		for i, s := range p.ReviewScores {
+			scores[i] = strconv.FormatFloat(s, 'f', -1, 64)
+			sum += s
+		}
+		parts = append(parts, fmt.Sprintf("scores %s (avg %.1f)", strings.Join(scores, ", "), sum/float64(len(p.ReviewScores))))
+	}
+	return strings.Join(parts, " · ")
 }
 
 // Fetcher retrieves recent academic papers for given topics.
 type Fetcher interface {
 	Fetch(ctx context.Context, topic string, maxResults int) ([]Paper, error)
 	FetchMultiple(ctx context.Context, topics []string, maxResults int) ([]Paper, error)
-}
\ No newline at end of file
+}
+
+// SinceFetcher is implemented by fetchers that can page back through results
+// to a point in time, so that no paper is missed on busy days.
+type SinceFetcher interface {
+	// FetchSince returns every paper matching the topics that was published
+	// after since, newest first. When the fetcher's safety bound stops it
+	// early, until is the time up to which every matching paper was
+	// returned; otherwise until is zero.
+	FetchSince(ctx context.Context, topics []string, since time.Time) (papers []Paper, until time.Time, err error)
+}
+
+// mergePapers combines papers from several queries, dropping duplicates (by ID,
+// or URL when there is no ID), and returns at most maxResults, newest first.
+// A duplicate adds its topics to the paper that is kept.
+func mergePapers(papers []Paper, maxResults int) []Paper {
+	index := make(map[string]int, len(papers))
+	merged := make([]Paper, 0, len(papers))
+	for _, p := range papers {
+		key := p.ID
+		if key == "" {
+			key = p.URL
+		}
+		if i, ok := index[key]; ok && key != "" {
+			for _, t := range p.Topics {
+				if !slices.Contains(merged[i].Topics, t) {
+					merged[i].Topics = append(merged[i].Topics, t)
+				}
+			}
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, p)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Published.After(merged[j].Published)
+	})
+
+	if len(merged) > maxResults {
+		merged = merged[:maxResults]
+	}
+	return merged
+}
+
+// tagTopic records topic on every paper and returns papers.
+func tagTopic(papers []Paper, topic string) []Paper {
+	for i := range papers {
+		if !slices.Contains(papers[i].Topics, topic) {
+			papers[i].Topics = append(papers[i].Topics, topic)
+		}
+	}
+	return papers
+}
+
+// selectByTopic merges the papers fetched for several topics and keeps at
+// most maxResults, newest first. The newest minPerTopic papers of every topic
+// are kept before the remaining places are filled; minPerTopic <= 0 means an
+// even share of maxResults.
+func selectByTopic(papers []Paper, topics []string, maxResults, minPerTopic int) []Paper {
+	merged := mergePapers(papers, len(papers))
+	if len(merged) <= maxResults || len(topics) == 0 {
+		return merged
+	}
+
+	share := maxResults / len(topics)
+	if minPerTopic > 0 {
+		share = min(minPerTopic, share)
+	}
+
+	keep := make([]bool, len(merged))
+	kept := 0
+	for _, topic := range topics {
+		n := 0
+		for i, p := range merged {
+			if n >= share {
+				break
+			}
+			if !slices.Contains(p.Topics, topic) {
+				continue
+			}
+			n++
+			if !keep[i] {
+				keep[i] = true
+				kept++
+			}
+		}
+	}
+	for i := range merged {
+		if kept >= maxResults {
+			break
+		}
+		if !keep[i] {
+			keep[i] = true
+			kept++
+		}
+	}
+
+	selected := make([]Paper, 0, kept)
+	for i, p := range merged {
+		if keep[i] {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
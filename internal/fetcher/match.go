@@ -0,0 +1,30 @@
+package fetcher
+
+import "strings"
+
+// matchesTopic reports whether every word of topic occurs in text, ignoring
+// case. It is used by sources that cannot search by keyword server-side.
+func matchesTopic(text, topic string) bool {
+	words := strings.Fields(strings.ToLower(topic))
+	if len(words) == 0 {
+		return false
+	}
+	text = strings.ToLower(text)
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchingTopics returns the topics that match text.
+func matchingTopics(text string, topics []string) []string {
+	var matched []string
+	for _, topic := range topics {
+		if matchesTopic(text, topic) {
+			matched = append(matched, topic)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,272 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/retry"
+)
+
+// OpenReview API v2 response structures. Every content field is wrapped in
+// an object with a "value" key.
+
+type openreviewResponse struct {
+	Notes []openreviewNote `json:"notes"`
+	Count int              `json:"count"`
+}
+
+type openreviewNote struct {
+	ID      string `json:"id"`
+	Forum   string `json:"forum"`
+	Cdate   int64  `json:"cdate"` // Creation time in milliseconds
+	Pdate   int64  `json:"pdate"` // Publication time in milliseconds, if published
+	Content struct {
+		Title       openreviewString  `json:"title"`
+		Authors     openreviewStrings `json:"authors"`
+		Abstract    openreviewString  `json:"abstract"`
+		Keywords    openreviewStrings `json:"keywords"`
+		PrimaryArea openreviewString  `json:"primary_area"`
+		Venue       openreviewString  `json:"venue"`
+	} `json:"content"`
+	Details struct {
+		Replies []openreviewReply `json:"replies"`
+	} `json:"details"`
+}
+
+type openreviewString struct {
+	Value string `json:"value"`
+}
+
+type openreviewStrings struct {
+	Value []string `json:"value"`
+}
+
+type openreviewReply struct {
+	Invitations []string `json:"invitations"`
+	Content     map[string]struct {
+		Value json.RawMessage `json:"value"`
+	} `json:"content"`
+}
+
+// openreviewScoreFields are the reply fields holding a reviewer's overall
+// rating, in order of preference. Venues name them differently.
+var openreviewScoreFields = []string{"rating", "recommendation", "overall_assessment", "score"}
+
+// leadingNumberRegex matches the number at the start of ratings such as
+// "6: marginally above the acceptance threshold".
+var leadingNumberRegex = regexp.MustCompile(`^\s*(-?\d+(?:\.\d+)?)`)
+
+// OpenReviewFetcher fetches the submissions of one OpenReview venue, e.g.
+// "ICLR.cc/2027/Conference", together with their reviews and decisions. The
+// API has no keyword search over submissions, so topics are matched locally
+// against the title, abstract and keywords.
+type OpenReviewFetcher struct {
+	client      *http.Client
+	baseURL     string
+	venue       string
+	invitation  string // Submission invitation; defaults to "<venue>/-/Submission"
+	pageSize    int
+	maxPages    int // Safety bound on the number of pages per run
+	limiter     *rateLimiter
+	retryConfig retry.Config
+}
+
+// NewOpenReviewFetcher creates a fetcher for venue. An empty invitation uses
+// the conventional "<venue>/-/Submission".
+func NewOpenReviewFetcher(venue, invitation string) *OpenReviewFetcher {
+	return &OpenReviewFetcher{
+		client:     &http.Client{Timeout: 60 * time.Second},
+		baseURL:    "https://api2.openreview.net",
+		venue:      venue,
+		invitation: invitation,
+		pageSize:   1000,
+		maxPages:   20,
+		limiter:    newRateLimiter(500 * time.Millisecond),
+		retryConfig: retry.Config{
+			MaxRetries: 3,
+			BaseDelay:  2 * time.Second,
+		},
+	}
+}
+
+func (f *OpenReviewFetcher) Fetch(ctx context.Context, topic string, maxResults int) ([]Paper, error) {
+	return f.FetchMultiple(ctx, []string{topic}, maxResults)
+}
+
+func (f *OpenReviewFetcher) FetchMultiple(ctx context.Context, topics []string, maxResults int) ([]Paper, error) {
+	invitation := f.invitation
+	if invitation == "" {
+		invitation = f.venue + "/-/Submission"
+	}
+
+	var matched []Paper
+	for page := 0; page < f.maxPages; page++ {
+		offset := page * f.pageSize
+
+		var resp *openreviewResponse
+		err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
+			var err error
+			resp, err = f.fetchPage(ctx, invitation, offset)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, note := range resp.Notes {
+			text := strings.Join(append([]string{note.Content.Title.Value, note.Content.Abstract.Value}, note.Content.Keywords.Value...), " ")
+			if m := matchingTopics(text, topics); len(m) > 0 {
+				p := note.toPaper()
+				p.Topics = m
+				matched = append(matched, p)
+			}
+		}
+
+		if len(resp.Notes) < f.pageSize || offset+len(resp.Notes) >= resp.Count {
+			break
+		}
+	}
+
+	return mergePapers(matched, maxResults), nil
+}
+
+func (f *OpenReviewFetcher) fetchPage(ctx context.Context, invitation string, offset int) (*openreviewResponse, error) {
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("invitation", invitation)
+	query.Set("details", "replies")
+	query.Set("sort", "cdate:desc")
+	query.Set("limit", strconv.Itoa(f.pageSize))
+	query.Set("offset", strconv.Itoa(offset))
+
+	reqURL := fmt.Sprintf("%s/notes?%s", f.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("openreview: failed to create request: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openreview: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openreview: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("openreview: failed to read response: %w", err)
+	}
+
+	var page openreviewResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("openreview: failed to parse JSON: %w", err)
+	}
+	return &page, nil
+}
+
+// toPaper converts a submission into a Paper, collecting the decision and
+// review ratings from its replies. The paper links to the PDF; the forum with
+// the reviews is kept in ForumURL.
+func (n openreviewNote) toPaper() Paper {
+	ms := n.Pdate
+	if ms == 0 {
+		ms = n.Cdate
+	}
+	var published time.Time
+	if ms > 0 {
+		published = time.UnixMilli(ms).UTC()
+	}
+
+	forum := n.Forum
+	if forum == "" {
+		forum = n.ID
+	}
+
+	category := n.Content.PrimaryArea.Value
+	if category == "" {
+		category = n.Content.Venue.Value
+	}
+
+	pdfURL := "https://openreview.net/pdf?id=" + url.QueryEscape(n.ID)
+
+	p := Paper{
+		ID:         "openreview:" + n.ID,
+		Title:      strings.TrimSpace(n.Content.Title.Value),
+		Authors:    n.Content.Authors.Value,
+		Abstract:   strings.TrimSpace(n.Content.Abstract.Value),
+		URL:        pdfURL,
+		Published:  published,
+		Category:   strings.ReplaceAll(category, "_", " "),
+		Categories: n.Content.Keywords.Value,
+		PDFURL:     pdfURL,
+		ForumURL:   "https://openreview.net/forum?id=" + url.QueryEscape(forum),
+	}
+
+	for _, reply := range n.Details.Replies {
+		switch {
+		case reply.hasInvitation("/-/Decision"):
+			if v, ok := reply.Content["decision"]; ok {
+				var decision string
+				if json.Unmarshal(v.Value, &decision) == nil {
+					p.Decision = decision
+				}
+			}
+		case reply.hasInvitation("/-/Official_Review"):
+			for _, field := range openreviewScoreFields {
+				if v, ok := reply.Content[field]; ok {
+					if score, ok := parseReviewScore(v.Value); ok {
+						p.ReviewScores = append(p.ReviewScores, score)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return p
+}
+
+// hasInvitation reports whether the reply was posted under an invitation
+// ending in suffix, e.g. "/-/Decision".
+func (r openreviewReply) hasInvitation(suffix string) bool {
+	for _, inv := range r.Invitations {
+		if strings.HasSuffix(inv, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseReviewScore reads a rating that is either a number or a string
+// starting with one.
+func parseReviewScore(raw json.RawMessage) (float64, bool) {
+	var n float64
+	if json.Unmarshal(raw, &n) == nil {
+		return n, true
+	}
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return 0, false
+	}
+	m := leadingNumberRegex.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	return n, err == nil
+}
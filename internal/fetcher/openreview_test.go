@@ -0,0 +1,128 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const openreviewPage = `{
+  "count": 2,
+  "notes": [
+    {
+      "id": "abc123",
+      "forum": "abc123",
+      "cdate": 1727740800000,
+      "content": {
+        "title": {"value": "Scaling Diffusion Transformers"},
+        "authors": {"value": ["Alice", "Bob"]},
+        "abstract": {"value": "We scale diffusion models."},
+        "keywords": {"value": ["diffusion", "generative models"]},
+        "primary_area": {"value": "generative_models"},
+        "venue": {"value": "ICLR 2027 Poster"}
+      },
+      "details": {
+        "replies": [
+          {"invitations": ["ICLR.cc/2027/Conference/Submission1/-/Official_Review"], "content": {"rating": {"value": "6: marginally above the acceptance threshold"}}},
+          {"invitations": ["ICLR.cc/2027/Conference/Submission1/-/Official_Review"], "content": {"rating": {"value": 8}}},
+          {"invitations": ["ICLR.cc/2027/Conference/Submission1/-/Official_Comment"], "content": {"comment": {"value": "Thanks!"}}},
+          {"invitations": ["ICLR.cc/2027/Conference/Submission1/-/Decision"], "content": {"decision": {"value": "Accept (poster)"}}}
+        ]
+      }
+    },
+    {
+      "id": "def456",
+      "forum": "def456",
+      "cdate": 1727827200000,
+      "content": {
+        "title": {"value": "Robust Reinforcement Learning"},
+        "authors": {"value": ["Charlie"]},
+        "abstract": {"value": "Policies that resist perturbations."},
+        "keywords": {"value": ["reinforcement learning"]}
+      },
+      "details": {"replies": []}
+    }
+  ]
+}`
+
+func TestOpenReviewFetchMapsReviews(t *testing.T) {
+	var invitations []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/notes" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("details") != "replies" {
+			t.Errorf("Expected details=replies, got %q", r.URL.Query().Get("details"))
+		}
+		invitations = append(invitations, r.URL.Query().Get("invitation"))
+		fmt.Fprint(w, openreviewPage)
+	}))
+	defer ts.Close()
+
+	f := &OpenReviewFetcher{client: ts.Client(), baseURL: ts.URL, venue: "ICLR.cc/2027/Conference", pageSize: 1000, maxPages: 5}
+
+	papers, err := f.Fetch(context.Background(), "diffusion", 10)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+
+	if len(invitations) != 1 || invitations[0] != "ICLR.cc/2027/Conference/-/Submission" {
+		t.Errorf("Expected a single page for the default submission invitation, got %v", invitations)
+	}
+	if len(papers) != 1 {
+		t.Fatalf("Expected 1 matching paper, got %d", len(papers))
+	}
+
+	p := papers[0]
+	if p.ID != "openreview:abc123" || p.URL != "https://openreview.net/pdf?id=abc123" {
+		t.Errorf("Unexpected ID/URL: %q / %q", p.ID, p.URL)
+	}
+	if p.ForumURL != "https://openreview.net/forum?id=abc123" {
+		t.Errorf("Unexpected forum URL: %q", p.ForumURL)
+	}
+	if p.Decision != "Accept (poster)" {
+		t.Errorf("Unexpected decision: %q", p.Decision)
+	}
+	if len(p.ReviewScores) != 2 || p.ReviewScores[0] != 6 || p.ReviewScores[1] != 8 {
+		t.Errorf("Unexpected review scores: %v", p.ReviewScores)
+	}
+	if p.Category != "generative models" || len(p.Categories) != 2 {
+		t.Errorf("Unexpected category/keywords: %q / %v", p.Category, p.Categories)
+	}
+	if p.Published.Format("2006-01-02") != "2024-10-01" {
+		t.Errorf("Unexpected published date: %v", p.Published)
+	}
+}
+
+func TestOpenReviewFetchBadStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	f := &OpenReviewFetcher{client: ts.Client(), baseURL: ts.URL, venue: "X", pageSize: 10, maxPages: 1}
+
+	_, err := f.Fetch(context.Background(), "test", 5)
+	if err == nil || !contains(err.Error(), "unexpected status 403") {
+		t.Errorf("Expected 'unexpected status 403' error, got: %v", err)
+	}
+}
+
+func TestPaperReviewInfo(t *testing.T) {
+	tests := []struct {
+		paper Paper
+		want  string
+	}{
+		{Paper{}, ""},
+		{Paper{Decision: "Reject"}, "Reject"},
+		{Paper{ReviewScores: []float64{5.5, 6}}, "scores 5.5, 6 (avg 5.8)"},
+		{Paper{Decision: "Accept (oral)", ReviewScores: []float64{8}}, "Accept (oral) · scores 8 (avg 8.0)"},
+	}
+	for _, tt := range tests {
+		if got := tt.paper.ReviewInfo(); got != tt.want {
+			t.Errorf("ReviewInfo() = %q, want %q", got, tt.want)
+		}
+	}
+}
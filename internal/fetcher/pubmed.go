@@ -0,0 +1,367 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/retry"
+)
+
+// NCBI E-utilities response structures
+
+type esearchResponse struct {
+	Result struct {
+		Count  string   `json:"count"`
+		IDList []string `json:"idlist"`
+	} `json:"esearchresult"`
+}
+
+type pubmedArticleSet struct {
+	XMLName  xml.Name        `xml:"PubmedArticleSet"`
+	Articles []pubmedArticle `xml:"PubmedArticle"`
+}
+
+type pubmedArticle struct {
+	Citation struct {
+		PMID    string `xml:"PMID"`
+		Article struct {
+			Journal struct {
+				Title   string `xml:"Title"`
+				PubDate struct {
+					Year        string `xml:"Year"`
+					Month       string `xml:"Month"`
+					Day         string `xml:"Day"`
+					MedlineDate string `xml:"MedlineDate"`
+				} `xml:"JournalIssue>PubDate"`
+			} `xml:"Journal"`
+			Title    pubmedText   `xml:"ArticleTitle"`
+			Abstract []pubmedText `xml:"Abstract>AbstractText"`
+			Authors  []struct {
+				LastName       string `xml:"LastName"`
+				ForeName       string `xml:"ForeName"`
+				CollectiveName string `xml:"CollectiveName"`
+			} `xml:"AuthorList>Author"`
+			ArticleDates []struct {
+				Year  string `xml:"Year"`
+				Month string `xml:"Month"`
+				Day   string `xml:"Day"`
+			} `xml:"ArticleDate"`
+		} `xml:"Article"`
+		MeshHeadings []string `xml:"MeshHeadingList>MeshHeading>DescriptorName"`
+	} `xml:"MedlineCitation"`
+	ArticleIDs []struct {
+		Type  string `xml:"IdType,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"PubmedData>ArticleIdList>ArticleId"`
+}
+
+// pubmedText captures element content including inline markup such as <i> or <sup>.
+type pubmedText struct {
+	Label string `xml:"Label,attr"`
+	Inner string `xml:",innerxml"`
+}
+
+var xmlTagRegex = regexp.MustCompile(`<[^>]+>`)
+
+// String returns the element text with inline markup removed.
+func (t pubmedText) String() string {
+	return strings.TrimSpace(html.UnescapeString(xmlTagRegex.ReplaceAllString(t.Inner, "")))
+}
+
+// efetchBatchSize is the number of PMIDs requested per EFetch call.
+const efetchBatchSize = 200
+
+// PubMedFetcher fetches biomedical papers from PubMed via NCBI E-utilities:
+// ESearch finds the newest matching PMIDs, EFetch retrieves their records.
+type PubMedFetcher struct {
+	client      *http.Client
+	baseURL     string
+	apiKey      string
+	email       string
+	days        int // Only papers published in the last days (0 = no limit)
+	limiter     *rateLimiter
+	retryConfig retry.Config
+}
+
+// NewPubMedFetcher creates a fetcher. NCBI allows 3 requests per second
+// without an API key and 10 with one; email identifies the caller to NCBI.
+func NewPubMedFetcher(apiKey, email string, days int) *PubMedFetcher {
+	interval := time.Second / 3
+	if apiKey != "" {
+		interval = time.Second / 10
+	}
+	return &PubMedFetcher{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		baseURL: "https://eutils.ncbi.nlm.nih.gov/entrez/eutils",
+		apiKey:  apiKey,
+		email:   email,
+		days:    days,
+		limiter: newRateLimiter(interval),
+		retryConfig: retry.Config{
+			MaxRetries: 3,
+			BaseDelay:  1 * time.Second,
+		},
+	}
+}
+
+func (f *PubMedFetcher) Fetch(ctx context.Context, topic string, maxResults int) ([]Paper, error) {
+	papers, err := f.fetchTerm(ctx, topic, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	return tagTopic(papers, topic), nil
+}
+
+func (f *PubMedFetcher) FetchMultiple(ctx context.Context, topics []string, maxResults int) ([]Paper, error) {
+	if len(topics) == 0 {
+		return []Paper{}, nil
+	}
+
+	// PubMed supports boolean queries, so all topics go into one search. The
+	// results do not say which topic matched, so papers are tagged with the
+	// topics their text matches.
+	terms := make([]string, len(topics))
+	for i, topic := range topics {
+		terms[i] = fmt.Sprintf("(%s)", topic)
+	}
+	papers, err := f.fetchTerm(ctx, strings.Join(terms, " OR "), maxResults)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range papers {
+		text := strings.Join(append([]string{p.Title, p.Abstract}, p.Categories...), " ")
+		papers[i].Topics = matchingTopics(text, topics)
+	}
+	return papers, nil
+}
+
+// fetchTerm searches for term and returns the newest matching records.
+func (f *PubMedFetcher) fetchTerm(ctx context.Context, term string, maxResults int) ([]Paper, error) {
+	var ids []string
+	err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
+		var err error
+		ids, err = f.search(ctx, term, maxResults)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var papers []Paper
+	for start := 0; start < len(ids); start += efetchBatchSize {
+		end := start + efetchBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var batch []Paper
+		err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
+			var err error
+			batch, err = f.fetchRecords(ctx, ids[start:end])
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		papers = append(papers, batch...)
+	}
+
+	return mergePapers(papers, maxResults), nil
+}
+
+// search runs ESearch and returns the newest matching PMIDs.
+func (f *PubMedFetcher) search(ctx context.Context, term string, maxResults int) ([]string, error) {
+	query := f.commonParams()
+	query.Set("term", term)
+	query.Set("retmax", strconv.Itoa(maxResults))
+	query.Set("retmode", "json")
+	query.Set("sort", "pub_date")
+	if f.days > 0 {
+		query.Set("datetype", "pdat")
+		query.Set("reldate", strconv.Itoa(f.days))
+	}
+
+	body, err := f.get(ctx, "esearch.fcgi", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var result esearchResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("pubmed: failed to parse ESearch JSON: %w", err)
+	}
+	return result.Result.IDList, nil
+}
+
+// fetchRecords runs EFetch for the given PMIDs and converts the records.
+func (f *PubMedFetcher) fetchRecords(ctx context.Context, ids []string) ([]Paper, error) {
+	query := f.commonParams()
+	query.Set("id", strings.Join(ids, ","))
+	query.Set("retmode", "xml")
+
+	body, err := f.get(ctx, "efetch.fcgi", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var set pubmedArticleSet
+	if err := xml.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("pubmed: failed to parse XML: %w", err)
+	}
+
+	papers := make([]Paper, 0, len(set.Articles))
+	for _, article := range set.Articles {
+		papers = append(papers, article.toPaper())
+	}
+	return papers, nil
+}
+
+func (f *PubMedFetcher) commonParams() url.Values {
+	query := url.Values{}
+	query.Set("db", "pubmed")
+	query.Set("tool", "daily-feed")
+	if f.email != "" {
+		query.Set("email", f.email)
+	}
+	if f.apiKey != "" {
+		query.Set("api_key", f.apiKey)
+	}
+	return query
+}
+
+func (f *PubMedFetcher) get(ctx context.Context, endpoint string, query url.Values) ([]byte, error) {
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/%s?%s", f.baseURL, endpoint, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("pubmed: failed to create request: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("pubmed: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pubmed: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("pubmed: failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// toPaper converts a PubMed record into a Paper. Structured abstracts keep
+// their section labels, and MeSH descriptors become the categories.
+func (a pubmedArticle) toPaper() Paper {
+	article := a.Citation.Article
+	pmid := strings.TrimSpace(a.Citation.PMID)
+
+	var sections []string
+	for _, text := range article.Abstract {
+		section := text.String()
+		if text.Label != "" {
+			section = fmt.Sprintf("%s: %s", text.Label, section)
+		}
+		sections = append(sections, section)
+	}
+
+	var authors []string
+	for _, au := range article.Authors {
+		switch {
+		case au.CollectiveName != "":
+			authors = append(authors, strings.TrimSpace(au.CollectiveName))
+		case au.ForeName != "":
+			authors = append(authors, strings.TrimSpace(au.ForeName+" "+au.LastName))
+		case au.LastName != "":
+			authors = append(authors, strings.TrimSpace(au.LastName))
+		}
+	}
+
+	var published time.Time
+	for _, d := range article.ArticleDates {
+		if published = parsePubMedDate(d.Year, d.Month, d.Day); !published.IsZero() {
+			break
+		}
+	}
+	if published.IsZero() {
+		pd := article.Journal.PubDate
+		published = parsePubMedDate(pd.Year, pd.Month, pd.Day)
+		if published.IsZero() && pd.MedlineDate != "" {
+			// e.g. "2025 Jan-Feb": use the year and first month
+			if fields := strings.Fields(pd.MedlineDate); len(fields) > 0 {
+				month := ""
+				if len(fields) > 1 {
+					month = strings.SplitN(fields[1], "-", 2)[0]
+				}
+				published = parsePubMedDate(fields[0], month, "")
+			}
+		}
+	}
+
+	categories := make([]string, 0, len(a.Citation.MeshHeadings))
+	for _, mesh := range a.Citation.MeshHeadings {
+		categories = append(categories, strings.TrimSpace(mesh))
+	}
+
+	var doi string
+	for _, id := range a.ArticleIDs {
+		if id.Type == "doi" {
+			doi = strings.TrimSpace(id.Value)
+			break
+		}
+	}
+
+	return Paper{
+		ID:         "pmid:" + pmid,
+		Title:      article.Title.String(),
+		Authors:    authors,
+		Abstract:   strings.Join(sections, "\n"),
+		URL:        fmt.Sprintf("https://pubmed.ncbi.nlm.nih.gov/%s/", pmid),
+		Published:  published,
+		Category:   strings.TrimSpace(article.Journal.Title),
+		Categories: categories,
+		DOI:        doi,
+	}
+}
+
+// parsePubMedDate parses PubMed date parts. Month may be numeric or an
+// abbreviation such as "Jan"; missing month or day default to 1.
+func parsePubMedDate(year, month, day string) time.Time {
+	y, err := strconv.Atoi(strings.TrimSpace(year))
+	if err != nil {
+		return time.Time{}
+	}
+
+	m := 1
+	month = strings.TrimSpace(month)
+	if n, err := strconv.Atoi(month); err == nil {
+		m = n
+	} else if t, err := time.Parse("Jan", month); err == nil {
+		m = int(t.Month())
+	}
+
+	d := 1
+	if n, err := strconv.Atoi(strings.TrimSpace(day)); err == nil {
+		d = n
+	}
+
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,225 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const sampleESearch = `{"header":{"type":"esearch","version":"0.3"},"esearchresult":{"count":"2","retmax":"2","retstart":"0","idlist":["39000001","39000002"]}}`
+
+const samplePubMedXML = `<?xml version="1.0" ?>
+<!DOCTYPE PubmedArticleSet PUBLIC "-//NLM//DTD PubMedArticle, 1st January 2024//EN" "https://dtd.nlm.nih.gov/ncbi/pubmed/out/pubmed_240101.dtd">
+<PubmedArticleSet>
+  <PubmedArticle>
+    <MedlineCitation Status="MEDLINE" Owner="NLM">
+      <PMID Version="1">39000001</PMID>
+      <Article PubModel="Print-Electronic">
+        <Journal>
+          <Title>Nature medicine</Title>
+          <JournalIssue CitedMedium="Internet">
+            <PubDate><Year>2025</Year><Month>Feb</Month></PubDate>
+          </JournalIssue>
+        </Journal>
+        <ArticleTitle>CRISPR screening of <i>T cell</i> exhaustion.</ArticleTitle>
+        <Abstract>
+          <AbstractText Label="BACKGROUND" NlmCategory="BACKGROUND">T cells become exhausted.</AbstractText>
+          <AbstractText Label="RESULTS" NlmCategory="RESULTS">We found 12 regulators &amp; validated 3.</AbstractText>
+        </Abstract>
+        <AuthorList CompleteYN="Y">
+          <Author ValidYN="Y"><LastName>Smith</LastName><ForeName>Jane</ForeName><Initials>J</Initials></Author>
+          <Author ValidYN="Y"><CollectiveName>Immune Atlas Consortium</CollectiveName></Author>
+        </AuthorList>
+        <ArticleDate DateType="Electronic"><Year>2025</Year><Month>01</Month><Day>20</Day></ArticleDate>
+      </Article>
+      <MeshHeadingList>
+        <MeshHeading><DescriptorName UI="D006801" MajorTopicYN="N">Humans</DescriptorName></MeshHeading>
+        <MeshHeading><DescriptorName UI="D013601" MajorTopicYN="Y">T-Lymphocytes</DescriptorName><QualifierName UI="Q000502">physiology</QualifierName></MeshHeading>
+      </MeshHeadingList>
+    </MedlineCitation>
+  </PubmedArticle>
+  <PubmedArticle>
+    <MedlineCitation Status="PubMed-not-MEDLINE" Owner="NLM">
+      <PMID Version="1">39000002</PMID>
+      <Article PubModel="Print">
+        <Journal>
+          <Title>Journal of biomedical informatics</Title>
+          <JournalIssue><PubDate><MedlineDate>2024 Nov-Dec</MedlineDate></PubDate></JournalIssue>
+        </Journal>
+        <ArticleTitle>Unstructured abstract paper.</ArticleTitle>
+        <Abstract><AbstractText>Plain abstract text.</AbstractText></Abstract>
+        <AuthorList><Author><LastName>Doe</LastName></Author></AuthorList>
+      </Article>
+    </MedlineCitation>
+  </PubmedArticle>
+</PubmedArticleSet>`
+
+func TestPubMedFetchSearchesAndParses(t *testing.T) {
+	var searchQuery, fetchQuery string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/esearch.fcgi":
+			searchQuery = r.URL.RawQuery
+			fmt.Fprint(w, sampleESearch)
+		case "/efetch.fcgi":
+			fetchQuery = r.URL.RawQuery
+			fmt.Fprint(w, samplePubMedXML)
+		default:
+			t.Errorf("Unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	f := &PubMedFetcher{
+		client:  ts.Client(),
+		baseURL: ts.URL,
+		apiKey:  "ncbi-key",
+		email:   "lab@example.com",
+		days:    7,
+	}
+
+	papers, err := f.Fetch(context.Background(), "T cell exhaustion", 10)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+
+	for _, want := range []string{"db=pubmed", "term=T+cell+exhaustion", "retmax=10", "reldate=7", "datetype=pdat", "api_key=ncbi-key", "email=lab%40example.com", "sort=pub_date"} {
+		if !strings.Contains(searchQuery, want) {
+			t.Errorf("Expected ESearch query to contain %q, got %q", want, searchQuery)
+		}
+	}
+	if !strings.Contains(fetchQuery, "id=39000001%2C39000002") || !strings.Contains(fetchQuery, "retmode=xml") {
+		t.Errorf("Expected EFetch for both PMIDs, got %q", fetchQuery)
+	}
+
+	if len(papers) != 2 {
+		t.Fatalf("Expected 2 papers, got %d", len(papers))
+	}
+
+	p := papers[0]
+	if p.ID != "pmid:39000001" || p.URL != "https://pubmed.ncbi.nlm.nih.gov/39000001/" {
+		t.Errorf("Unexpected ID/URL: %q %q", p.ID, p.URL)
+	}
+	if p.Title != "CRISPR screening of T cell exhaustion." {
+		t.Errorf("Expected inline markup to be stripped from title, got %q", p.Title)
+	}
+	if p.Abstract != "BACKGROUND: T cells become exhausted.\nRESULTS: We found 12 regulators & validated 3." {
+		t.Errorf("Expected labelled structured abstract, got %q", p.Abstract)
+	}
+	if len(p.Authors) != 2 || p.Authors[0] != "Jane Smith" || p.Authors[1] != "Immune Atlas Consortium" {
+		t.Errorf("Unexpected authors: %v", p.Authors)
+	}
+	if p.Published.Format("2006-01-02") != "2025-01-20" {
+		t.Errorf("Expected electronic article date, got %v", p.Published)
+	}
+	if p.Category != "Nature medicine" {
+		t.Errorf("Expected journal as category, got %q", p.Category)
+	}
+	if len(p.Categories) != 2 || p.Categories[1] != "T-Lymphocytes" {
+		t.Errorf("Expected MeSH terms as categories, got %v", p.Categories)
+	}
+	if len(p.Topics) != 1 || p.Topics[0] != "T cell exhaustion" {
+		t.Errorf("Expected the paper tagged with its topic, got %v", p.Topics)
+	}
+
+	p2 := papers[1]
+	if p2.Abstract != "Plain abstract text." {
+		t.Errorf("Unexpected unstructured abstract: %q", p2.Abstract)
+	}
+	if p2.Published.Format("2006-01-02") != "2024-11-01" {
+		t.Errorf("Expected MedlineDate fallback, got %v", p2.Published)
+	}
+}
+
+func TestPubMedFetchMultipleCombinesTopics(t *testing.T) {
+	var term string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/esearch.fcgi" {
+			term = r.URL.Query().Get("term")
+			fmt.Fprint(w, `{"esearchresult":{"count":"0","idlist":[]}}`)
+			return
+		}
+		t.Errorf("Did not expect EFetch without IDs")
+	}))
+	defer ts.Close()
+
+	f := &PubMedFetcher{client: ts.Client(), baseURL: ts.URL}
+
+	papers, err := f.FetchMultiple(context.Background(), []string{"sepsis", "antibiotic resistance"}, 5)
+	if err != nil {
+		t.Fatalf("FetchMultiple returned error: %v", err)
+	}
+	if len(papers) != 0 {
+		t.Errorf("Expected no papers, got %d", len(papers))
+	}
+	if term != "(sepsis) OR (antibiotic resistance)" {
+		t.Errorf("Unexpected combined term %q", term)
+	}
+}
+
+func TestPubMedFetchMultipleTagsMatchingTopics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/esearch.fcgi" {
+			fmt.Fprint(w, sampleESearch)
+			return
+		}
+		fmt.Fprint(w, samplePubMedXML)
+	}))
+	defer ts.Close()
+
+	f := &PubMedFetcher{client: ts.Client(), baseURL: ts.URL}
+
+	papers, err := f.FetchMultiple(context.Background(), []string{"T cell", "lymphocytes", "abstract"}, 10)
+	if err != nil {
+		t.Fatalf("FetchMultiple returned error: %v", err)
+	}
+	if len(papers) != 2 {
+		t.Fatalf("Expected 2 papers, got %d", len(papers))
+	}
+	if topics := papers[0].Topics; len(topics) != 2 || topics[0] != "T cell" || topics[1] != "lymphocytes" {
+		t.Errorf("Expected topics matched in the title and MeSH terms, got %v", topics)
+	}
+	if topics := papers[1].Topics; len(topics) != 1 || topics[0] != "abstract" {
+		t.Errorf("Expected the topic matched in the abstract, got %v", topics)
+	}
+}
+
+func TestParsePubMedDate(t *testing.T) {
+	tests := []struct {
+		year, month, day string
+		want             string
+	}{
+		{"2025", "Jan", "15", "2025-01-15"},
+		{"2025", "03", "", "2025-03-01"},
+		{"2025", "", "", "2025-01-01"},
+		{"", "Jan", "1", "0001-01-01"},
+	}
+	for _, tt := range tests {
+		if got := parsePubMedDate(tt.year, tt.month, tt.day).Format("2006-01-02"); got != tt.want {
+			t.Errorf("parsePubMedDate(%q, %q, %q) = %s, want %s", tt.year, tt.month, tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestPubMedMedlineDate(t *testing.T) {
+	tests := []struct {
+		medlineDate string
+		want        string
+	}{
+		{"2024 Nov-Dec", "2024-11-01"},
+		{"2023", "2023-01-01"},
+		{"   ", "0001-01-01"},
+	}
+	for _, tt := range tests {
+		var a pubmedArticle
+		a.Citation.PMID = "39000003"
+		a.Citation.Article.Journal.PubDate.MedlineDate = tt.medlineDate
+		if got := a.toPaper().Published.Format("2006-01-02"); got != tt.want {
+			t.Errorf("MedlineDate %q: got %s, want %s", tt.medlineDate, got, tt.want)
+		}
+	}
+}
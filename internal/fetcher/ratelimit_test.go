@@ -0,0 +1,36 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSpacesRequests(t *testing.T) {
+	l := newRateLimiter(30 * time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait returned error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("Expected 3 requests to take at least 60ms, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterNilAndCancel(t *testing.T) {
+	var nilLimiter *rateLimiter
+	if err := nilLimiter.Wait(context.Background()); err != nil {
+		t.Errorf("Expected nil limiter not to wait, got %v", err)
+	}
+
+	l := newRateLimiter(time.Hour)
+	l.Wait(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Error("Expected cancelled context to abort the wait")
+	}
+}
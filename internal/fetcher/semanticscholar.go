@@ -0,0 +1,224 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/retry"
+)
+
+// Semantic Scholar Graph API response structures
+
+type s2SearchResponse struct {
+	Total  int       `json:"total"`
+	Offset int       `json:"offset"`
+	Next   *int      `json:"next"`
+	Data   []s2Paper `json:"data"`
+}
+
+type s2Paper struct {
+	PaperID         string         `json:"paperId"`
+	Title           string         `json:"title"`
+	Abstract        *string        `json:"abstract"`
+	URL             string         `json:"url"`
+	Year            int            `json:"year"`
+	PublicationDate *string        `json:"publicationDate"`
+	Authors         []s2Author     `json:"authors"`
+	FieldsOfStudy   []string       `json:"fieldsOfStudy"`
+	ExternalIDs     map[string]any `json:"externalIds"`
+	OpenAccessPDF   *struct {
+		URL string `json:"url"`
+	} `json:"openAccessPdf"`
+}
+
+type s2Author struct {
+	Name string `json:"name"`
+}
+
+const s2Fields = "title,abstract,url,year,publicationDate,authors,fieldsOfStudy,externalIds,openAccessPdf"
+
+// SemanticScholarFetcher fetches papers from the Semantic Scholar Graph API.
+// Unlike arXiv it also covers journals and venues that never post preprints.
+type SemanticScholarFetcher struct {
+	client      *http.Client
+	baseURL     string
+	apiKey      string
+	days        int      // Only papers published in the last days (0 = no limit)
+	venues      []string // Optional venue filter
+	pageSize    int
+	limiter     *rateLimiter
+	retryConfig retry.Config
+}
+
+// NewSemanticScholarFetcher creates a fetcher. apiKey may be empty, in which
+// case requests share the (much lower) unauthenticated rate limit.
+func NewSemanticScholarFetcher(apiKey string, days int, venues []string) *SemanticScholarFetcher {
+	// 1 request per second with a key; the shared pool allows roughly one every 3 seconds.
+	interval := 3 * time.Second
+	if apiKey != "" {
+		interval = 1 * time.Second
+	}
+	return &SemanticScholarFetcher{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		baseURL:  "https://api.semanticscholar.org/graph/v1",
+		apiKey:   apiKey,
+		days:     days,
+		venues:   venues,
+		pageSize: 100,
+		limiter:  newRateLimiter(interval),
+		retryConfig: retry.Config{
+			MaxRetries: 3,
+			BaseDelay:  2 * time.Second,
+		},
+	}
+}
+
+func (f *SemanticScholarFetcher) Fetch(ctx context.Context, topic string, maxResults int) ([]Paper, error) {
+	var papers []Paper
+	for offset := 0; len(papers) < maxResults; {
+		limit := f.pageSize
+		if limit <= 0 || limit > maxResults-len(papers) {
+			limit = maxResults - len(papers)
+		}
+
+		var page *s2SearchResponse
+		err := retry.WithBackoff(ctx, f.retryConfig, func(ctx context.Context) error {
+			var err error
+			page, err = f.fetchPage(ctx, topic, offset, limit)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sp := range page.Data {
+			papers = append(papers, sp.toPaper())
+		}
+		if page.Next == nil || len(page.Data) == 0 {
+			break
+		}
+		offset = *page.Next
+	}
+
+	return tagTopic(mergePapers(papers, maxResults), topic), nil
+}
+
+func (f *SemanticScholarFetcher) FetchMultiple(ctx context.Context, topics []string, maxResults int) ([]Paper, error) {
+	var all []Paper
+	for _, topic := range topics {
+		papers, err := f.Fetch(ctx, topic, maxResults)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, papers...)
+	}
+	return mergePapers(all, maxResults), nil
+}
+
+func (f *SemanticScholarFetcher) fetchPage(ctx context.Context, topic string, offset, limit int) (*s2SearchResponse, error) {
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("query", topic)
+	query.Set("fields", s2Fields)
+	query.Set("offset", fmt.Sprintf("%d", offset))
+	query.Set("limit", fmt.Sprintf("%d", limit))
+	if f.days > 0 {
+		from := time.Now().AddDate(0, 0, -f.days).Format("2006-01-02")
+		query.Set("publicationDateOrYear", from+":")
+	}
+	if len(f.venues) > 0 {
+		query.Set("venue", strings.Join(f.venues, ","))
+	}
+
+	reqURL := fmt.Sprintf("%s/paper/search?%s", f.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("semanticscholar: failed to create request: %w", err)
+	}
+	if f.apiKey != "" {
+		req.Header.Set("x-api-key", f.apiKey)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("semanticscholar: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("semanticscholar: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("semanticscholar: failed to read response: %w", err)
+	}
+
+	var page s2SearchResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("semanticscholar: failed to parse JSON: %w", err)
+	}
+	return &page, nil
+}
+
+// toPaper converts a Graph API paper into a Paper. Papers that are also on
+// arXiv keep their arXiv ID so they are recognised across sources.
+func (sp s2Paper) toPaper() Paper {
+	id := "s2:" + sp.PaperID
+	if arxivID, ok := sp.ExternalIDs["ArXiv"].(string); ok && arxivID != "" {
+		id = arxivID
+	}
+
+	var published time.Time
+	if sp.PublicationDate != nil {
+		published, _ = time.Parse("2006-01-02", *sp.PublicationDate)
+	}
+	if published.IsZero() && sp.Year > 0 {
+		published = time.Date(sp.Year, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	var abstract string
+	if sp.Abstract != nil {
+		abstract = strings.TrimSpace(*sp.Abstract)
+	}
+
+	authors := make([]string, len(sp.Authors))
+	for i, a := range sp.Authors {
+		authors[i] = strings.TrimSpace(a.Name)
+	}
+
+	var category string
+	if len(sp.FieldsOfStudy) > 0 {
+		category = sp.FieldsOfStudy[0]
+	}
+
+	doi, _ := sp.ExternalIDs["DOI"].(string)
+
+	var pdfURL string
+	if sp.OpenAccessPDF != nil {
+		pdfURL = sp.OpenAccessPDF.URL
+	}
+
+	return Paper{
+		ID:         id,
+		Title:      strings.TrimSpace(sp.Title),
+		Authors:    authors,
+		Abstract:   abstract,
+		URL:        sp.URL,
+		Published:  published,
+		Category:   category,
+		Categories: sp.FieldsOfStudy,
+		DOI:        doi,
+		PDFURL:     pdfURL,
+	}
+}
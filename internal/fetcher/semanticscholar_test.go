@@ -0,0 +1,195 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const s2PageOne = `{
+  "total": 3,
+  "offset": 0,
+  "next": 2,
+  "data": [
+    {
+      "paperId": "abc123",
+      "title": "  Protein Folding at Scale  ",
+      "abstract": "We fold proteins.",
+      "url": "https://www.semanticscholar.org/paper/abc123",
+      "year": 2025,
+      "publicationDate": "2025-01-10",
+      "authors": [{"name": "Alice"}, {"name": "Bob"}],
+      "fieldsOfStudy": ["Biology", "Computer Science"],
+      "externalIds": {"DOI": "10.1000/xyz", "CorpusId": 42}
+    },
+    {
+      "paperId": "def456",
+      "title": "Also on arXiv",
+      "abstract": null,
+      "url": "https://www.semanticscholar.org/paper/def456",
+      "year": 2025,
+      "publicationDate": "2025-01-12",
+      "authors": [{"name": "Charlie"}],
+      "fieldsOfStudy": null,
+      "externalIds": {"ArXiv": "2501.00002"}
+    }
+  ]
+}`
+
+const s2PageTwo = `{
+  "total": 3,
+  "offset": 2,
+  "data": [
+    {
+      "paperId": "ghi789",
+      "title": "Year Only",
+      "abstract": "No exact date.",
+      "url": "https://www.semanticscholar.org/paper/ghi789",
+      "year": 2024,
+      "publicationDate": null,
+      "authors": [],
+      "fieldsOfStudy": ["Medicine"],
+      "externalIds": {}
+    }
+  ]
+}`
+
+func TestSemanticScholarFetchPagesAndMaps(t *testing.T) {
+	var offsets []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/paper/search" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("Expected x-api-key 'test-key', got %q", r.Header.Get("x-api-key"))
+		}
+		if r.URL.Query().Get("query") != "protein folding" {
+			t.Errorf("Expected query 'protein folding', got %q", r.URL.Query().Get("query"))
+		}
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+		w.Header().Set("Content-Type", "application/json")
+		if offset == "0" {
+			fmt.Fprint(w, s2PageOne)
+		} else {
+			fmt.Fprint(w, s2PageTwo)
+		}
+	}))
+	defer ts.Close()
+
+	f := &SemanticScholarFetcher{
+		client:   ts.Client(),
+		baseURL:  ts.URL,
+		apiKey:   "test-key",
+		pageSize: 2,
+	}
+
+	papers, err := f.Fetch(context.Background(), "protein folding", 10)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+
+	if len(offsets) != 2 || offsets[1] != "2" {
+		t.Errorf("Expected two pages at offsets 0 and 2, got %v", offsets)
+	}
+	if len(papers) != 3 {
+		t.Fatalf("Expected 3 papers, got %d", len(papers))
+	}
+
+	// Newest first
+	p := papers[0]
+	if p.ID != "2501.00002" {
+		t.Errorf("Expected arXiv ID to be used when available, got %q", p.ID)
+	}
+	if p.Abstract != "" {
+		t.Errorf("Expected empty abstract for null, got %q", p.Abstract)
+	}
+	if len(p.Topics) != 1 || p.Topics[0] != "protein folding" {
+		t.Errorf("Expected the paper tagged with its topic, got %v", p.Topics)
+	}
+
+	p = papers[1]
+	if p.ID != "s2:abc123" {
+		t.Errorf("Expected s2-prefixed ID, got %q", p.ID)
+	}
+	if p.Title != "Protein Folding at Scale" {
+		t.Errorf("Expected trimmed title, got %q", p.Title)
+	}
+	if len(p.Authors) != 2 || p.Authors[1] != "Bob" {
+		t.Errorf("Unexpected authors: %v", p.Authors)
+	}
+	if p.Category != "Biology" || len(p.Categories) != 2 {
+		t.Errorf("Expected fields of study as categories, got %q / %v", p.Category, p.Categories)
+	}
+	if p.Published.Format("2006-01-02") != "2025-01-10" {
+		t.Errorf("Unexpected published date: %v", p.Published)
+	}
+	if p.URL != "https://www.semanticscholar.org/paper/abc123" {
+		t.Errorf("Unexpected URL: %q", p.URL)
+	}
+
+	if papers[2].Published.Year() != 2024 {
+		t.Errorf("Expected year fallback for missing publication date, got %v", papers[2].Published)
+	}
+}
+
+func TestSemanticScholarFetchRespectsMaxResults(t *testing.T) {
+	var limits []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		fmt.Fprint(w, s2PageOne)
+	}))
+	defer ts.Close()
+
+	f := &SemanticScholarFetcher{client: ts.Client(), baseURL: ts.URL, pageSize: 100}
+
+	papers, err := f.Fetch(context.Background(), "protein folding", 2)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if len(papers) != 2 {
+		t.Errorf("Expected 2 papers, got %d", len(papers))
+	}
+	if len(limits) != 1 || limits[0] != "2" {
+		t.Errorf("Expected a single request with limit=2, got %v", limits)
+	}
+}
+
+func TestSemanticScholarFetchMultipleDedupes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, s2PageTwo)
+	}))
+	defer ts.Close()
+
+	f := &SemanticScholarFetcher{client: ts.Client(), baseURL: ts.URL, pageSize: 100}
+
+	papers, err := f.FetchMultiple(context.Background(), []string{"a", "b"}, 10)
+	if err != nil {
+		t.Fatalf("FetchMultiple returned error: %v", err)
+	}
+	if len(papers) != 1 {
+		t.Fatalf("Expected duplicate papers across topics to be merged, got %d", len(papers))
+	}
+	if topics := papers[0].Topics; len(topics) != 2 || topics[0] != "a" || topics[1] != "b" {
+		t.Errorf("Expected the merged paper tagged with both topics, got %v", topics)
+	}
+}
+
+func TestSemanticScholarFetchBadStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	f := &SemanticScholarFetcher{client: ts.Client(), baseURL: ts.URL, pageSize: 100}
+
+	_, err := f.Fetch(context.Background(), "test", 5)
+	if err == nil {
+		t.Fatal("Expected error for 403 status code")
+	}
+	if !contains(err.Error(), "unexpected status 403") {
+		t.Errorf("Expected 'unexpected status 403' error, got: %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+package fulltext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// block is a heading or a run of body text in document order.
+type block struct {
+	heading bool
+	text    string
+}
+
+// Section kinds, in the order they appear in an excerpt.
+var sectionKinds = []string{"introduction", "method", "conclusion"}
+
+// sectionKeywords classify a section heading by kind. The first matching
+// kind in sectionKinds order wins.
+var sectionKeywords = map[string][]string{
+	"introduction": {"introduction"},
+	"method":       {"method", "approach", "framework", "model", "architecture", "algorithm", "proposed"},
+	"conclusion":   {"conclusion", "concluding", "discussion", "summary"},
+}
+
+// sectionKind returns the kind of a section heading such as "3 Method", or ""
+// for sections that are not part of the excerpt.
+func sectionKind(heading string) string {
+	heading = strings.ToLower(heading)
+	for _, kind := range sectionKinds {
+		for _, kw := range sectionKeywords[kind] {
+			if strings.Contains(heading, kw) {
+				return kind
+			}
+		}
+	}
+	return ""
+}
+
+// excerpt joins the first introduction, method and conclusion sections of a
+// document, each under its heading, and bounds the result to maxChars bytes.
+// It is empty when none of the sections were found.
+func excerpt(blocks []block, maxChars int) string {
+	type section struct{ heading, text string }
+	found := make(map[string]*section)
+	var current *section
+	for _, b := range blocks {
+		if b.heading {
+			current = nil
+			kind := sectionKind(b.text)
+			if kind != "" && found[kind] == nil {
+				current = &section{heading: b.text}
+				found[kind] = current
+			}
+			continue
+		}
+		if current != nil {
+			current.text = strings.TrimSpace(current.text + " " + b.text)
+		}
+	}
+
+	var sections []*section
+	for _, kind := range sectionKinds {
+		if s := found[kind]; s != nil && s.text != "" {
+			sections = append(sections, s)
+		}
+	}
+	if len(sections) == 0 {
+		return ""
+	}
+
+	perSection := maxChars / len(sections)
+	parts := make([]string, len(sections))
+	for i, s := range sections {
+		parts[i] = s.heading + "\n" + truncate(s.text, perSection-len(s.heading)-3)
+	}
+	return truncate(strings.Join(parts, "\n\n"), maxChars)
+}
+
+// truncate cuts s to at most n bytes, preferably at a word boundary, and marks
+// the cut with an ellipsis.
+func truncate(s string, n int) string {
+	const ellipsis = " …"
+	if len(s) <= n {
+		return s
+	}
+	n -= len(ellipsis)
+	if n <= 0 {
+		return ""
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	cut := s[:n]
+	if i := strings.LastIndexByte(cut, ' '); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + ellipsis
+}
+
+// HTML extraction, for the LaTeXML markup used by ar5iv and arXiv HTML.
+
+var (
+	htmlSectionRegex = regexp.MustCompile(`(?is)<h2[^>]*>(.*?)</h2>`)
+	htmlMathRegex    = regexp.MustCompile(`(?is)<math[^>]*?alttext="([^"]*)"[^>]*>.*?</math>`)
+	htmlDropRegexes  = []*regexp.Regexp{
+		regexp.MustCompile(`(?is)<script.*?</script>`),
+		regexp.MustCompile(`(?is)<style.*?</style>`),
+		regexp.MustCompile(`(?is)<nav.*?</nav>`),
+		regexp.MustCompile(`(?is)<figure.*?</figure>`),
+		regexp.MustCompile(`(?is)<table.*?</table>`),
+	}
+	htmlBlockTagRegex = regexp.MustCompile(`(?i)</?(p|div|li|ul|ol|br|h[1-6]|section|blockquote)\b[^>]*>`)
+	htmlTagRegex      = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// htmlBlocks splits an HTML paper at its section (h2) headings. Subsection
+// headings stay part of the section text, and math is replaced by its
+// LaTeX source.
+func htmlBlocks(data []byte) []block {
+	s := htmlMathRegex.ReplaceAllString(string(data), " $1 ")
+	for _, re := range htmlDropRegexes {
+		s = re.ReplaceAllString(s, " ")
+	}
+
+	matches := htmlSectionRegex.FindAllStringSubmatchIndex(s, -1)
+	blocks := make([]block, 0, 2*len(matches))
+	for i, m := range matches {
+		end := len(s)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		blocks = append(blocks,
+			block{heading: true, text: htmlText(s[m[2]:m[3]])},
+			block{text: htmlText(s[m[1]:end])},
+		)
+	}
+	return blocks
+}
+
+// htmlText returns the plain text of an HTML fragment.
+func htmlText(s string) string {
+	s = htmlBlockTagRegex.ReplaceAllString(s, " ")
+	s = htmlTagRegex.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// PDF extraction. This handles the text operators of uncompressed and
+// Flate-compressed content streams with simple font encodings, as produced by
+// pdfTeX; it is a fallback for papers without an HTML rendering.
+
+var (
+	pdfStreamRegex = regexp.MustCompile(`(?s)stream\r?\n(.*?)endstream`)
+	// A line is a section heading when it is short and numbered ("3 Method",
+	// "IV. Results") or consists of a common unnumbered heading.
+	pdfHeadingRegex = regexp.MustCompile(`^((\d{1,2}|[IVX]{1,5})\.?\s+[A-Z][^.]{2,60}|(?i:abstract|introduction|conclusions?|discussion|references|acknowledge?ments?))$`)
+)
+
+// pdfLigatures maps the OT1 ligature codes to their letters.
+var pdfLigatures = map[byte]string{0x0b: "ff", 0x0c: "fi", 0x0d: "fl", 0x0e: "ffi", 0x0f: "ffl"}
+
+// pdfBlocks extracts the text lines of a PDF and marks the heading lines.
+func pdfBlocks(data []byte) []block {
+	var lines []string
+	for _, m := range pdfStreamRegex.FindAllSubmatch(data, -1) {
+		content := m[1]
+		if r, err := zlib.NewReader(bytes.NewReader(content)); err == nil {
+			if inflated, err := io.ReadAll(io.LimitReader(r, maxDocumentSize)); err == nil {
+				content = inflated
+			}
+		}
+		if !bytes.Contains(content, []byte("BT")) ||
+			(!bytes.Contains(content, []byte("Tj")) && !bytes.Contains(content, []byte("TJ"))) {
+			continue
+		}
+		lines = append(lines, pdfTextLines(content)...)
+	}
+
+	var blocks []block
+	for _, line := range lines {
+		if pdfHeadingRegex.MatchString(line) {
+			blocks = append(blocks, block{heading: true, text: line})
+			continue
+		}
+		if n := len(blocks); n > 0 && !blocks[n-1].heading {
+			prev := blocks[n-1].text
+			if strings.HasSuffix(prev, "-") {
+				// Rejoin a word hyphenated across lines.
+				blocks[n-1].text = prev[:len(prev)-1] + line
+			} else {
+				blocks[n-1].text = prev + " " + line
+			}
+			continue
+		}
+		blocks = append(blocks, block{text: line})
+	}
+	return blocks
+}
+
+// pdfTextLines runs through the operators of a content stream and returns the
+// text shown on each line. Large negative kerning in TJ arrays becomes a space.
+func pdfTextLines(content []byte) []string {
+	var lines []string
+	var line strings.Builder
+	flush := func() {
+		if s := strings.Join(strings.Fields(line.String()), " "); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+
+	inArray := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '(':
+			s, n := pdfLiteral(content[i:])
+			line.WriteString(s)
+			i += n - 1
+		case c == '[':
+			inArray = true
+		case c == ']':
+			inArray = false
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '<':
+			// Hex strings and dictionaries carry no text we can decode.
+			for i < len(content) && content[i] != '>' {
+				i++
+			}
+		case c == '/':
+			for i+1 < len(content) && !pdfDelimiter(content[i+1]) {
+				i++
+			}
+		case c == '-' || c == '.' || isDigit(c):
+			j := i + 1
+			for j < len(content) && (content[j] == '.' || isDigit(content[j])) {
+				j++
+			}
+			if inArray {
+				if v, err := strconv.ParseFloat(string(content[i:j]), 64); err == nil && v < -200 {
+					line.WriteByte(' ')
+				}
+			}
+			i = j - 1
+		case isLetter(c) || c == '\'' || c == '"':
+			j := i + 1
+			for j < len(content) && (isLetter(content[j]) || content[j] == '*') {
+				j++
+			}
+			switch string(content[i:j]) {
+			case "Td", "TD", "T*", "Tm", "ET", "'", "\"":
+				flush()
+			}
+			i = j - 1
+		}
+	}
+	flush()
+	return lines
+}
+
+// pdfLiteral decodes the literal string at the start of data, which begins
+// with '(', and returns it with the number of bytes consumed.
+func pdfLiteral(data []byte) (string, int) {
+	var sb strings.Builder
+	depth := 0
+	i := 0
+	for ; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return sb.String(), i + 1
+			}
+		case '\\':
+			i++
+			if i >= len(data) {
+				return sb.String(), i
+			}
+			switch e := data[i]; e {
+			case 'n', 'r', 't', 'f', 'b':
+				sb.WriteByte(' ')
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					j := i
+					for j < len(data) && j < i+3 && data[j] >= '0' && data[j] <= '7' {
+						j++
+					}
+					v, _ := strconv.ParseUint(string(data[i:j]), 8, 8)
+					writePDFByte(&sb, byte(v))
+					i = j - 1
+				} else {
+					sb.WriteByte(e)
+				}
+			}
+			continue
+		}
+		writePDFByte(&sb, c)
+	}
+	return sb.String(), i
+}
+
+// writePDFByte writes a character code, keeping printable ASCII and ligatures.
+func writePDFByte(sb *strings.Builder, b byte) {
+	if lig, ok := pdfLigatures[b]; ok {
+		sb.WriteString(lig)
+		return
+	}
+	if b >= 0x20 && b < 0x7f {
+		sb.WriteByte(b)
+	}
+}
+
+func pdfDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f()<>[]{}/%", c) >= 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
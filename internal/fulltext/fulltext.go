@@ -0,0 +1,199 @@
+// Package fulltext downloads the full text of arXiv papers and extracts the
+// introduction, method and conclusion sections, so that the summarizer sees
+// more than the abstract.
+package fulltext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+// maxDocumentSize bounds the bytes read from a single HTML page or PDF.
+const maxDocumentSize = 20 << 20
+
+// arxivIDRegex matches new-style (2401.12345) and old-style (hep-th/9901001)
+// arXiv IDs, as set on Paper.ID by the arxiv fetcher.
+var arxivIDRegex = regexp.MustCompile(`^(\d{4}\.\d{4,5}|[a-z-]+(\.[A-Za-z-]+)?/\d{7})$`)
+
+// Enricher adds full-text excerpts to papers. It prefers the ar5iv-style HTML
+// rendering of an arXiv paper and falls back to its PDF.
+type Enricher struct {
+	client      *http.Client
+	htmlBaseURL string        // HTML rendering, followed by the arXiv ID
+	pdfBaseURL  string        // PDF of arXiv papers without a PDF link, followed by the arXiv ID
+	cacheDir    string        // Extracted excerpts by paper ID; empty disables the cache
+	maxChars    int           // Bound on the excerpt of a single paper
+	budget      int           // Downloads allowed per Enrich call
+	delay       time.Duration // Pause between downloads
+}
+
+// New returns an Enricher caching excerpts in cacheDir. At most budget
+// documents are downloaded per run, and excerpts are cut to maxChars.
+func New(cacheDir string, budget, maxChars int) (*Enricher, error) {
+	if cacheDir != "" {
+		if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+			return nil, fmt.Errorf("fulltext: failed to create %s: %w", cacheDir, err)
+		}
+	}
+	return &Enricher{
+		client:      &http.Client{Timeout: 60 * time.Second},
+		htmlBaseURL: "https://ar5iv.labs.arxiv.org/html/",
+		pdfBaseURL:  "https://arxiv.org/pdf/",
+		cacheDir:    cacheDir,
+		maxChars:    maxChars,
+		budget:      budget,
+		delay:       3 * time.Second,
+	}, nil
+}
+
+// Enrich sets Paper.Excerpt on the papers it can get the full text of, in
+// order, until the download budget is spent. Cached excerpts cost nothing.
+// Failures are logged and leave the paper with its abstract only.
+func (e *Enricher) Enrich(ctx context.Context, papers []fetcher.Paper) []fetcher.Paper {
+	downloads, enriched, skipped := 0, 0, 0
+	for i := range papers {
+		p := &papers[i]
+		if p.Excerpt != "" || p.ID == "" {
+			continue
+		}
+
+		if excerpt, ok := e.cached(*p); ok {
+			p.Excerpt = excerpt
+			enriched++
+			continue
+		}
+
+		sources := e.sources(*p)
+		if len(sources) == 0 {
+			continue
+		}
+		if downloads >= e.budget {
+			skipped++
+			continue
+		}
+
+		for _, src := range sources {
+			if downloads >= e.budget {
+				break
+			}
+			if downloads > 0 {
+				select {
+				case <-ctx.Done():
+					return papers
+				case <-time.After(e.delay):
+				}
+			}
+			downloads++
+
+			excerpt, err := e.download(ctx, src)
+			if err != nil {
+				log.Printf("WARNING: full text of %s: %v", p.ID, err)
+				continue
+			}
+			if excerpt == "" {
+				continue
+			}
+			p.Excerpt = excerpt
+			enriched++
+			e.store(*p, excerpt)
+			break
+		}
+	}
+	log.Printf("Added full-text excerpts to %d of %d papers (%d downloads)", enriched, len(papers), downloads)
+	if skipped > 0 {
+		log.Printf("Full-text download budget of %d spent, %d papers keep their abstracts only", e.budget, skipped)
+	}
+	return papers
+}
+
+// source is a document to download and the extractor for its format.
+type source struct {
+	url     string
+	extract func([]byte) []block
+}
+
+// sources lists where the full text of p can be found, in order of
+// preference: the HTML rendering for arXiv papers, then the PDF.
+func (e *Enricher) sources(p fetcher.Paper) []source {
+	var sources []source
+	isArxiv := arxivIDRegex.MatchString(p.ID)
+	if isArxiv {
+		sources = append(sources, source{url: e.htmlBaseURL + p.ID, extract: htmlBlocks})
+	}
+	switch {
+	case p.PDFURL != "":
+		sources = append(sources, source{url: p.PDFURL, extract: pdfBlocks})
+	case isArxiv:
+		sources = append(sources, source{url: e.pdfBaseURL + p.ID, extract: pdfBlocks})
+	}
+	return sources
+}
+
+// download fetches a document and returns the excerpt extracted from it.
+func (e *Enricher) download(ctx context.Context, src source) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d from %s", resp.StatusCode, src.url)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", src.url, err)
+	}
+
+	return excerpt(src.extract(data), e.maxChars), nil
+}
+
+// cachePath returns the cache file of a paper. The ID may contain slashes;
+// the version, or the update time for sources without versions, is part of
+// the name so that a revised paper is downloaded again.
+func (e *Enricher) cachePath(p fetcher.Paper) string {
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(p.ID)
+	switch {
+	case p.Version > 0:
+		name += fmt.Sprintf("v%d", p.Version)
+	case !p.Updated.IsZero():
+		name += "-" + p.Updated.UTC().Format("20060102150405")
+	}
+	return filepath.Join(e.cacheDir, name+".txt")
+}
+
+func (e *Enricher) cached(p fetcher.Paper) (string, bool) {
+	if e.cacheDir == "" {
+		return "", false
+	}
+	data, err := os.ReadFile(e.cachePath(p))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+func (e *Enricher) store(p fetcher.Paper, excerpt string) {
+	if e.cacheDir == "" {
+		return
+	}
+	if err := os.WriteFile(e.cachePath(p), []byte(excerpt), 0o644); err != nil {
+		log.Printf("WARNING: failed to cache full text of %s: %v", p.ID, err)
+	}
+}
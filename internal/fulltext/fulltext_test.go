@@ -0,0 +1,171 @@
+package fulltext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+// newTestEnricher serves the testdata directory: /html/<id> from <id>.html
+// and everything else as files. It returns the enricher and the request log.
+func newTestEnricher(t *testing.T, budget int) (*Enricher, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []string
+	files := http.FileServer(http.Dir("testdata"))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		if id, ok := strings.CutPrefix(r.URL.Path, "/html/"); ok {
+			r.URL.Path = "/" + id + ".html"
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	e, err := New(t.TempDir(), budget, 2000)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	e.client = ts.Client()
+	e.htmlBaseURL = ts.URL + "/html/"
+	e.pdfBaseURL = ts.URL + "/pdf/"
+	e.delay = 0
+	return e, &requests
+}
+
+func TestHTMLExcerpt(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "2401.00001.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := excerpt(htmlBlocks(data), 2000)
+
+	for _, want := range []string{
+		"1 Introduction\nTransformers scale quadratically with the sequence length n , which makes long documents expensive.",
+		"attending to <10% of positions",
+		"3 Method\nEach query selects the top- k keys by a learned router.",
+		"Router training The router is trained",
+		"5 Conclusion\nSparse routing makes long-document models practical.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected excerpt to contain %q, got:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"Related Work", "Experiments", "Figure 1", "Vaswani", "MathJax", "ltx_"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("Expected excerpt not to contain %q, got:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestPDFExcerpt(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "paper.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := excerpt(pdfBlocks(data), 2000)
+
+	want := "1 Introduction\nPredicting protein structures is a longstanding problem in biology.\n\n" +
+		"2 Methods\nWe train a diffusion model on backbone (C) coordinates and define a new loss.\n\n" +
+		"3 Conclusion\nDiffusion models fold proteins accurately."
+	if got != want {
+		t.Errorf("Unexpected excerpt:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestExcerptIsBounded(t *testing.T) {
+	blocks := []block{
+		{heading: true, text: "Introduction"},
+		{text: strings.Repeat("word ", 500)},
+		{heading: true, text: "Conclusion"},
+		{text: strings.Repeat("end ", 500)},
+	}
+
+	got := excerpt(blocks, 300)
+	if len(got) > 300 {
+		t.Errorf("Expected at most 300 bytes, got %d", len(got))
+	}
+	if !strings.Contains(got, "Introduction\nword") || !strings.Contains(got, "Conclusion\nend") {
+		t.Errorf("Expected both sections to share the bound, got %q", got)
+	}
+}
+
+func TestEnrichFallsBackToPDF(t *testing.T) {
+	e, requests := newTestEnricher(t, 10)
+	papers := []fetcher.Paper{
+		{ID: "2401.00001"},
+		{ID: "2401.00002", PDFURL: strings.TrimSuffix(e.pdfBaseURL, "/pdf/") + "/paper.pdf"},
+		{ID: "doi:10.1101/2025.01.01.000001"}, // No full text source
+	}
+
+	papers = e.Enrich(context.Background(), papers)
+
+	if !strings.Contains(papers[0].Excerpt, "learned router") {
+		t.Errorf("Expected HTML excerpt for the first paper, got %q", papers[0].Excerpt)
+	}
+	if !strings.Contains(papers[1].Excerpt, "Diffusion models fold proteins") {
+		t.Errorf("Expected PDF excerpt for the second paper, got %q", papers[1].Excerpt)
+	}
+	if papers[2].Excerpt != "" {
+		t.Errorf("Expected no excerpt for a paper without a source, got %q", papers[2].Excerpt)
+	}
+
+	want := []string{"/html/2401.00001", "/html/2401.00002", "/paper.pdf"}
+	if strings.Join(*requests, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected requests %v, got %v", want, *requests)
+	}
+}
+
+func TestEnrichUsesCacheAndBudget(t *testing.T) {
+	e, requests := newTestEnricher(t, 1)
+
+	papers := e.Enrich(context.Background(), []fetcher.Paper{{ID: "2401.00001"}, {ID: "2401.00003"}})
+	if papers[0].Excerpt == "" {
+		t.Error("Expected the first paper to be enriched")
+	}
+	if papers[1].Excerpt != "" {
+		t.Error("Expected the second paper to be skipped once the budget is spent")
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("Expected 1 download within the budget, got %v", *requests)
+	}
+
+	// The excerpt now comes from the cache, leaving the budget for the other paper.
+	papers = e.Enrich(context.Background(), []fetcher.Paper{{ID: "2401.00001"}, {ID: "2401.00003"}})
+	if papers[0].Excerpt == "" {
+		t.Error("Expected the cached excerpt")
+	}
+	if len(*requests) != 2 || (*requests)[1] != "/html/2401.00003" {
+		t.Errorf("Expected only the uncached paper to be downloaded, got %v", *requests)
+	}
+}
+
+func TestEnrichDownloadsRevisedPapersAgain(t *testing.T) {
+	e, requests := newTestEnricher(t, 10)
+
+	for _, version := range []int{1, 1, 2} {
+		papers := e.Enrich(context.Background(), []fetcher.Paper{{ID: "2401.00001", Version: version}})
+		if papers[0].Excerpt == "" {
+			t.Errorf("v%d: expected an excerpt", version)
+		}
+	}
+	if len(*requests) != 2 {
+		t.Errorf("Expected v1 to be cached and v2 downloaded again, got %v", *requests)
+	}
+	for _, name := range []string{"2401.00001v1.txt", "2401.00001v2.txt"} {
+		if _, err := os.Stat(filepath.Join(e.cacheDir, name)); err != nil {
+			t.Errorf("Expected cache file %s: %v", name, err)
+		}
+	}
+}
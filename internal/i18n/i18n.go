@@ -0,0 +1,188 @@
+// Package i18n holds the message catalogs used to write digests in the
+// configured language: the text of digests written without a model, the
+// field labels of the model prompts and the chrome of the publishers
+// (headings, labels and date formats).
+//
+// Catalogs are YAML files named <language>.yaml. The built-in ones are
+// embedded; LoadDir adds more, or overrides built-in messages, from a
+// directory, so languages can be added without rebuilding.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed locales/*.yaml
+var builtinLocales embed.FS
+
+// Fallback is the language of the reference catalog, used for messages a
+// catalog does not define and for languages without a catalog.
+const Fallback = "en"
+
+// Locale is the message catalog of a language.
+type Locale struct {
+	Code        string            `yaml:"-"`            // e.g. "de"
+	Name        string            `yaml:"name"`         // Native name, e.g. "Deutsch"
+	EnglishName string            `yaml:"english_name"` // Used to name the language in English prompts
+	Months      []string          `yaml:"months"`       // Month names replacing the English ones in dates
+	Messages    map[string]string `yaml:"messages"`
+}
+
+var (
+	mu      sync.RWMutex
+	locales = mustLoadBuiltin()
+	builtin = languages(locales)
+)
+
+func mustLoadBuiltin() map[string]*Locale {
+	m := make(map[string]*Locale)
+	if err := load(m, builtinLocales, "locales"); err != nil {
+		panic(err)
+	}
+	if m[Fallback] == nil {
+		panic("i18n: no built-in " + Fallback + " catalog")
+	}
+	return m
+}
+
+// LoadDir loads the <language>.yaml catalogs in dir. A catalog of a language
+// that already has one overrides the messages it defines and keeps the rest.
+func LoadDir(dir string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	m := make(map[string]*Locale, len(locales))
+	for code, l := range locales {
+		m[code] = l
+	}
+	if err := load(m, os.DirFS(dir), "."); err != nil {
+		return err
+	}
+	locales = m
+	return nil
+}
+
+// load reads the catalogs in dir of fsys into m, merging them with those
+// already in m.
+func load(m map[string]*Locale, fsys fs.FS, dir string) error {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("i18n: failed to read catalog: %w", err)
+		}
+		code := strings.TrimSuffix(path.Base(name), ".yaml")
+		l := &Locale{Code: code}
+		if err := yaml.Unmarshal(data, l); err != nil {
+			return fmt.Errorf("i18n: invalid catalog %s: %w", path.Base(name), err)
+		}
+		if len(l.Months) != 0 && len(l.Months) != 12 {
+			return fmt.Errorf("i18n: catalog %s: months must list 12 names, got %d", path.Base(name), len(l.Months))
+		}
+		if old := m[code]; old != nil {
+			l = merge(old, l)
+		}
+		m[code] = l
+	}
+	return nil
+}
+
+// merge returns base with the fields and messages defined by override.
+func merge(base, override *Locale) *Locale {
+	l := *base
+	if override.Name != "" {
+		l.Name = override.Name
+	}
+	if override.EnglishName != "" {
+		l.EnglishName = override.EnglishName
+	}
+	if len(override.Months) > 0 {
+		l.Months = override.Months
+	}
+	l.Messages = make(map[string]string, len(base.Messages)+len(override.Messages))
+	for k, v := range base.Messages {
+		l.Messages[k] = v
+	}
+	for k, v := range override.Messages {
+		l.Messages[k] = v
+	}
+	return &l
+}
+
+func languages(m map[string]*Locale) []string {
+	codes := make([]string, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Builtin returns the languages with a built-in catalog, sorted.
+func Builtin() []string {
+	return append([]string(nil), builtin...)
+}
+
+// Has reports whether language has a catalog.
+func Has(language string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locales[language] != nil
+}
+
+// Get returns the catalog of language, or the English one when it has none.
+func Get(language string) *Locale {
+	mu.RLock()
+	defer mu.RUnlock()
+	if l := locales[language]; l != nil {
+		return l
+	}
+	return locales[Fallback]
+}
+
+// T formats the message key with args. Messages the catalog does not define
+// come from the English catalog; unknown keys format as the key itself.
+func (l *Locale) T(key string, args ...any) string {
+	msg, ok := l.Messages[key]
+	if !ok {
+		msg, ok = Get(Fallback).Messages[key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Date formats t with the layout of the message key, such as "date_long",
+// spelling the month in the language of the catalog.
+func (l *Locale) Date(t time.Time, key string) string {
+	s := t.Format(l.T(key))
+	if len(l.Months) == 12 {
+		s = strings.ReplaceAll(s, t.Month().String(), l.Months[t.Month()-1])
+	}
+	return s
+}
+
+// Language returns the English name of the language, for prompts.
+func (l *Locale) Language() string {
+	if l.EnglishName != "" {
+		return l.EnglishName
+	}
+	return l.Code
+}
@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sampleArgs are arguments of the types each message is formatted with.
+var sampleArgs = map[string][]any{
+	"no_papers":           {"llm"},
+	"extractive_overview": {"llm", 40, 5},
+	"extractive_terms":    {"agents"},
+	"title":               {"llm"},
+	"title_dated":         {"llm", "2025-01-15"},
+	"digest_title":        {"llm"},
+	"summarized_by":       {"openai/gpt-4o"},
+	"tokens":              {1000, 200, 0.5},
+}
+
+func TestBuiltinCatalogsComplete(t *testing.T) {
+	for _, want := range []string{"en", "ja", "zh", "ko", "de", "fr", "es"} {
+		if !Has(want) {
+			t.Errorf("Expected a built-in %s catalog", want)
+		}
+	}
+
+	en := Get("en")
+	for _, code := range Builtin() {
+		l := Get(code)
+		if l.Name == "" || l.EnglishName == "" {
+			t.Errorf("%s: expected name and english_name", code)
+		}
+		for key := range en.Messages {
+			if _, ok := l.Messages[key]; !ok {
+				t.Errorf("%s: missing message %s", code, key)
+				continue
+			}
+			if got := l.T(key, sampleArgs[key]...); strings.Contains(got, "%!") {
+				t.Errorf("%s: message %s does not format: %q", code, key, got)
+			}
+		}
+	}
+}
+
+func TestDate(t *testing.T) {
+	date := time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		language, key, want string
+	}{
+		{"en", "date_long", "January 15, 2025"},
+		{"en", "date_time", "2025-01-15 09:30"},
+		{"de", "date_long", "15. Januar 2025"},
+		{"es", "date_long", "15 de enero de 2025"},
+		{"ja", "date_long", "2025年1月15日"},
+		{"fr", "date_short", "15/01/2025"},
+	}
+	for _, tt := range tests {
+		if got := Get(tt.language).Date(date, tt.key); got != tt.want {
+			t.Errorf("%s %s: expected %q, got %q", tt.language, tt.key, tt.want, got)
+		}
+	}
+}
+
+func TestFallback(t *testing.T) {
+	if got := Get("xx").T("overview"); got != "Overview" {
+		t.Errorf("Expected English for a language without a catalog, got %q", got)
+	}
+	if got := Get("de").T("no_such_message"); got != "no_such_message" {
+		t.Errorf("Expected the key for an unknown message, got %q", got)
+	}
+	if got := Get("ja").T("extractive_overview", "llm", 40, 5); got != "「llm」に関する40件の論文から、キーワードの関連性と新しさで選んだ上位5件です。" {
+		t.Errorf("Unexpected Japanese overview: %q", got)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	eo := "name: Esperanto\nenglish_name: Esperanto\nmonths: [januaro, februaro, marto, aprilo, majo, junio, julio, aŭgusto, septembro, oktobro, novembro, decembro]\nmessages:\n  overview: Superrigardo\n  date_long: \"2 January 2006\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "eo.yaml"), []byte(eo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ko.yaml"), []byte("messages:\n  overview: 요약\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+
+	l := Get("eo")
+	if !Has("eo") || l.Code != "eo" || l.Language() != "Esperanto" {
+		t.Fatalf("Expected the eo catalog to be loaded, got %+v", l)
+	}
+	if got := l.T("overview"); got != "Superrigardo" {
+		t.Errorf("Expected the loaded message, got %q", got)
+	}
+	if got := l.T("key_points"); got != "Key Points" {
+		t.Errorf("Expected an English fallback for a missing message, got %q", got)
+	}
+	if got := l.Date(time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC), "date_long"); got != "3 aŭgusto 2025" {
+		t.Errorf("Unexpected date: %q", got)
+	}
+
+	// Overriding a built-in catalog keeps the messages it does not define.
+	ko := Get("ko")
+	if ko.T("overview") != "요약" || ko.T("key_points") != "핵심 포인트" || ko.Name != "한국어" {
+		t.Errorf("Expected the override merged into the built-in catalog, got %+v", ko)
+	}
+	if slices.Contains(Builtin(), "eo") {
+		t.Error("Expected loaded catalogs not to be reported as built in")
+	}
+}
+
+func TestLoadDirRejectsInvalidCatalogs(t *testing.T) {
+	tests := map[string]string{
+		"bad.yaml":    "messages: [not, a, map]\n",
+		"months.yaml": "months: [a, b]\n",
+	}
+	for name, content := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := LoadDir(dir); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: expected an error naming the catalog, got %v", name, err)
+		}
+	}
+}
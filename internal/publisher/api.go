@@ -0,0 +1,248 @@
+package publisher
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ryosukesatoh/daily-feed/internal/archive"
+	"github.com/ryosukesatoh/daily-feed/internal/runlog"
+	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+const (
+	apiDefaultPerPage = 20
+	apiMaxPerPage     = 100
+)
+
+// apiPage is the envelope for paginated list responses.
+type apiPage struct {
+	Data    any `json:"data"`
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	Total   int `json:"total"`
+}
+
+// apiPaperSummary is a paper summary together with the digest it appeared in.
+type apiPaperSummary struct {
+	summarizer.PaperSummary
+	DigestDate string   `json:"digest_date"`
+	Topics     []string `json:"topics"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// registerAPI adds the versioned JSON API routes to mux.
+func (wp *WebPublisher) registerAPI(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/digests", wp.handleAPIDigests)
+	mux.HandleFunc("GET /api/v1/digests/{date}", wp.handleAPIDigest)
+	mux.HandleFunc("GET /api/v1/papers", wp.handleAPIPapers)
+	mux.HandleFunc("GET /api/v1/papers/{id...}", wp.handleAPIPaper)
+	mux.HandleFunc("GET /api/v1/runs", wp.handleAPIRuns)
+	mux.HandleFunc("GET /api/v1/openapi.json", wp.handleAPISpec)
+}
+
+// SetRunLog exposes the records of past runs at /api/v1/runs.
+func (wp *WebPublisher) SetRunLog(l *runlog.Log) {
+	wp.runLog = l
+}
+
+func (wp *WebPublisher) handleAPIDigests(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	digests := filterDigests(wp.allDigests(), r.URL.Query().Get("topic"))
+	start, end := pageBounds(len(digests), page, perPage)
+	writeJSON(w, http.StatusOK, apiPage{
+		Data:    digests[start:end],
+		Page:    page,
+		PerPage: perPage,
+		Total:   len(digests),
+	})
+}
+
+func (wp *WebPublisher) handleAPIDigest(w http.ResponseWriter, r *http.Request) {
+	date := r.PathValue("date")
+
+	var digest *summarizer.Digest
+	if date == "latest" {
+		digest = wp.archive.Latest()
+	} else {
+		digest, _ = wp.archive.Get(date)
+	}
+	if digest == nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("no digest for %q", date))
+		return
+	}
+	writeJSON(w, http.StatusOK, digest)
+}
+
+func (wp *WebPublisher) handleAPIPapers(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	papers := allPaperSummaries(filterDigests(wp.allDigests(), r.URL.Query().Get("topic")))
+	start, end := pageBounds(len(papers), page, perPage)
+	writeJSON(w, http.StatusOK, apiPage{
+		Data:    papers[start:end],
+		Page:    page,
+		PerPage: perPage,
+		Total:   len(papers),
+	})
+}
+
+func (wp *WebPublisher) handleAPIPaper(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	// Digests are newest first, so the first match is the most recent summary.
+	for _, ps := range allPaperSummaries(wp.allDigests()) {
+		if ps.Paper.ID == id {
+			writeJSON(w, http.StatusOK, ps)
+			return
+		}
+	}
+	writeAPIError(w, http.StatusNotFound, fmt.Sprintf("no paper with id %q", id))
+}
+
+func (wp *WebPublisher) handleAPIRuns(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := parsePagination(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	runs := []runlog.Run{}
+	if wp.runLog != nil {
+		runs, err = wp.runLog.List()
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	start, end := pageBounds(len(runs), page, perPage)
+	writeJSON(w, http.StatusOK, apiPage{
+		Data:    runs[start:end],
+		Page:    page,
+		PerPage: perPage,
+		Total:   len(runs),
+	})
+}
+
+func (wp *WebPublisher) handleAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+// allDigests returns every archived digest, newest first.
+func (wp *WebPublisher) allDigests() []*summarizer.Digest {
+	dates := wp.archive.Dates()
+	digests := make([]*summarizer.Digest, 0, len(dates))
+	for _, date := range dates {
+		if d, ok := wp.archive.Get(date); ok {
+			digests = append(digests, d)
+		}
+	}
+	return digests
+}
+
+// filterDigests keeps the digests covering topic (case-insensitive). An empty topic keeps all.
+func filterDigests(digests []*summarizer.Digest, topic string) []*summarizer.Digest {
+	if topic == "" {
+		return digests
+	}
+	filtered := []*summarizer.Digest{}
+	for _, d := range digests {
+		for _, t := range digestTopics(d) {
+			if strings.EqualFold(t, topic) {
+				filtered = append(filtered, d)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// allPaperSummaries flattens digests into their paper summaries, keeping the order.
+func allPaperSummaries(digests []*summarizer.Digest) []apiPaperSummary {
+	papers := []apiPaperSummary{}
+	for _, d := range digests {
+		for _, ps := range d.Summaries {
+			papers = append(papers, apiPaperSummary{
+				PaperSummary: ps,
+				DigestDate:   d.Date.Format(archive.DateLayout),
+				Topics:       digestTopics(d),
+			})
+		}
+	}
+	return papers
+}
+
+// digestTopics returns the topics of a digest, falling back to the legacy single topic.
+func digestTopics(d *summarizer.Digest) []string {
+	if len(d.Topics) > 0 {
+		return d.Topics
+	}
+	if d.Topic != "" {
+		return []string{d.Topic}
+	}
+	return []string{}
+}
+
+// parsePagination reads the 1-based page and per_page query parameters.
+func parsePagination(r *http.Request) (page, perPage int, err error) {
+	page, perPage = 1, apiDefaultPerPage
+	q := r.URL.Query()
+	if v := q.Get("page"); v != "" {
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive integer")
+		}
+	}
+	if v := q.Get("per_page"); v != "" {
+		perPage, err = strconv.Atoi(v)
+		if err != nil || perPage < 1 || perPage > apiMaxPerPage {
+			return 0, 0, fmt.Errorf("per_page must be between 1 and %d", apiMaxPerPage)
+		}
+	}
+	return page, perPage, nil
+}
+
+// pageBounds returns the slice bounds of page within total items. Pages past
+// the end are empty; they are detected before multiplying, since page may be
+// as large as any int.
+func pageBounds(total, page, perPage int) (start, end int) {
+	if page-1 >= (total+perPage-1)/perPage {
+		return total, total
+	}
+	start = (page - 1) * perPage
+	end = start + perPage
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, apiError{Error: msg})
+}
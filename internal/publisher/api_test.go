@@ -0,0 +1,195 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/archive"
+	"github.com/ryosukesatoh/daily-feed/internal/runlog"
+	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
+)
+
+func newAPITestPublisher(t *testing.T) *WebPublisher {
+	t.Helper()
+	a, err := archive.Open("")
+	if err != nil {
+		t.Fatalf("archive.Open returned error: %v", err)
+	}
+	wp := NewWebPublisher(":0", a)
+
+	older := sampleDigest()
+	older.Date = time.Date(2025, 1, 14, 8, 0, 0, 0, time.UTC)
+	older.Topic = "quantum computing"
+	older.Summaries = older.Summaries[:1]
+	older.Summaries[0].Paper.ID = "2501.00001"
+	older.Summaries[0].Summary = "Older summary."
+
+	latest := sampleDigest()
+	latest.Summaries[0].Paper.ID = "2501.00001"
+
+	for _, d := range []*summarizer.Digest{older, latest} {
+		if err := wp.Publish(context.Background(), d); err != nil {
+			t.Fatalf("Publish returned error: %v", err)
+		}
+	}
+	return wp
+}
+
+func apiGet(t *testing.T, wp *WebPublisher, path string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	wp.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("GET %s: expected JSON content type, got %q", path, rec.Header().Get("Content-Type"))
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("GET %s: invalid JSON: %v\n%s", path, err, rec.Body.String())
+		}
+	}
+	return rec.Code
+}
+
+type digestPage struct {
+	Data    []summarizer.Digest `json:"data"`
+	Page    int                 `json:"page"`
+	PerPage int                 `json:"per_page"`
+	Total   int                 `json:"total"`
+}
+
+func TestAPIDigestsPaginationAndTopicFilter(t *testing.T) {
+	wp := newAPITestPublisher(t)
+
+	var page digestPage
+	if code := apiGet(t, wp, "/api/v1/digests?per_page=1", &page); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if page.Total != 2 || len(page.Data) != 1 || page.PerPage != 1 {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	if page.Data[0].Topic != "machine learning" {
+		t.Errorf("Expected newest digest first, got topic %q", page.Data[0].Topic)
+	}
+
+	apiGet(t, wp, "/api/v1/digests?per_page=1&page=2", &page)
+	if len(page.Data) != 1 || page.Data[0].Topic != "quantum computing" {
+		t.Errorf("Expected older digest on page 2, got %+v", page.Data)
+	}
+
+	apiGet(t, wp, "/api/v1/digests?page=5", &page)
+	if len(page.Data) != 0 || page.Total != 2 {
+		t.Errorf("Expected empty page past the end, got %+v", page)
+	}
+
+	for _, path := range []string{"/api/v1/digests?page=922337203685477581", "/api/v1/papers?page=922337203685477581&per_page=100", "/api/v1/runs?page=9223372036854775807"} {
+		if code := apiGet(t, wp, path, nil); code != http.StatusOK {
+			t.Errorf("GET %s: expected 200 for a page far past the end, got %d", path, code)
+		}
+	}
+
+	apiGet(t, wp, "/api/v1/digests?topic=Quantum%20Computing", &page)
+	if page.Total != 1 || page.Data[0].Topic != "quantum computing" {
+		t.Errorf("Expected topic filter to match case-insensitively, got %+v", page)
+	}
+
+	if code := apiGet(t, wp, "/api/v1/digests?per_page=1000", nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for per_page over the limit, got %d", code)
+	}
+}
+
+func TestAPIDigestByDate(t *testing.T) {
+	wp := newAPITestPublisher(t)
+
+	var d summarizer.Digest
+	if code := apiGet(t, wp, "/api/v1/digests/2025-01-14", &d); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if d.Topic != "quantum computing" {
+		t.Errorf("Expected 2025-01-14 digest, got topic %q", d.Topic)
+	}
+
+	apiGet(t, wp, "/api/v1/digests/latest", &d)
+	if d.Topic != "machine learning" {
+		t.Errorf("Expected latest digest, got topic %q", d.Topic)
+	}
+
+	var apiErr apiError
+	if code := apiGet(t, wp, "/api/v1/digests/2020-01-01", &apiErr); code != http.StatusNotFound || apiErr.Error == "" {
+		t.Errorf("Expected 404 with error message, got %d %+v", code, apiErr)
+	}
+}
+
+func TestAPIPapers(t *testing.T) {
+	wp := newAPITestPublisher(t)
+
+	var ps apiPaperSummary
+	if code := apiGet(t, wp, "/api/v1/papers/2501.00001", &ps); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if ps.Summary != "This is a summary of paper one." || ps.DigestDate != "2025-01-15" {
+		t.Errorf("Expected most recent summary of the paper, got %+v", ps)
+	}
+
+	if code := apiGet(t, wp, "/api/v1/papers/9999.99999", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown paper, got %d", code)
+	}
+
+	var page struct {
+		Data  []apiPaperSummary `json:"data"`
+		Total int               `json:"total"`
+	}
+	apiGet(t, wp, "/api/v1/papers?topic=quantum%20computing", &page)
+	if page.Total != 1 || page.Data[0].Summary != "Older summary." {
+		t.Errorf("Expected one paper for topic filter, got %+v", page)
+	}
+}
+
+func TestAPIRunsAndSpec(t *testing.T) {
+	wp := newAPITestPublisher(t)
+
+	var page struct {
+		Data  []runlog.Run `json:"data"`
+		Total int          `json:"total"`
+	}
+	apiGet(t, wp, "/api/v1/runs", &page)
+	if page.Total != 0 || page.Data == nil {
+		t.Errorf("Expected empty run list without a run log, got %+v", page)
+	}
+
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := runlog.Open(path)
+	if err != nil {
+		t.Fatalf("runlog.Open returned error: %v", err)
+	}
+	wp.SetRunLog(l)
+
+	var raw map[string]json.RawMessage
+	apiGet(t, wp, "/api/v1/runs", &raw)
+	if string(raw["data"]) != "[]" {
+		t.Errorf("Expected an empty data array for an empty run log, got %s", raw["data"])
+	}
+
+	l.Append(runlog.Run{Status: runlog.StatusSuccess, Summarized: 5})
+
+	apiGet(t, wp, "/api/v1/runs", &page)
+	if page.Total != 1 || page.Data[0].Summarized != 5 {
+		t.Errorf("Expected recorded run, got %+v", page)
+	}
+
+	var spec map[string]any
+	if code := apiGet(t, wp, "/api/v1/openapi.json", &spec); code != http.StatusOK {
+		t.Fatalf("Expected 200 for OpenAPI document, got %d", code)
+	}
+	if spec["openapi"] == nil || spec["paths"] == nil {
+		t.Errorf("Expected OpenAPI document, got keys %v", spec)
+	}
+}
@@ -84,14 +84,19 @@ func (d *DiscordPublisher) Publish(ctx context.Context, digest *summarizer.Diges
 
 // buildEmbeds creates the overview embed and one embed per paper.
 func (d *DiscordPublisher) buildEmbeds(digest *summarizer.Digest) []discordEmbed {
+	loc := locale(digest)
 	embeds := make([]discordEmbed, 0, len(digest.Summaries)+1)
 
 	// Overview embed
+	footer := loc.Date(digest.Date, "date_short")
+	if note := backendNote(digest); note != "" {
+		footer += " | " + note
+	}
 	overview := discordEmbed{
-		Title:       fmt.Sprintf("Daily Feed: %s", digest.GetTopicsString()),
+		Title:       loc.T("title", digest.GetTopicsString()),
 		Description: truncate(digest.Overview, 4096),
 		Color:       0x5865F2, // Discord blurple
-		Footer:      &discordEmbedFooter{Text: digest.Date.Format("2006-01-02")},
+		Footer:      &discordEmbedFooter{Text: truncate(footer, 2048)},
 		Timestamp:   digest.Date.Format(time.RFC3339),
 	}
 	embeds = append(embeds, overview)
@@ -108,12 +113,30 @@ func (d *DiscordPublisher) buildEmbeds(digest *summarizer.Digest) []discordEmbed
 		if len(ps.KeyPoints) > 0 {
 			e.Fields = []discordEmbedField{
 				{
-					Name:  "Key Points",
+					Name:  loc.T("key_points"),
 					Value: truncate(formatKeyPoints(ps.KeyPoints), 1024),
 				},
 			}
 		}
 
+		if notes := paperNotes(loc, ps.Paper); len(notes) > 0 {
+			e.Fields = append(e.Fields, discordEmbedField{
+				Name:  loc.T("notes"),
+				Value: truncate(strings.Join(notes, "\n"), 1024),
+			})
+		}
+		if links := paperLinks(loc, ps.Paper); len(links) > 0 {
+			md := make([]string, len(links))
+			for i, link := range links {
+				md[i] = fmt.Sprintf("[%s](%s)", link.Label, link.URL)
+			}
+			e.Fields = append(e.Fields, discordEmbedField{
+				Name:   loc.T("links"),
+				Value:  strings.Join(md, " \u00b7 "),
+				Inline: true,
+			})
+		}
+
 		// Footer with authors and category
 		var footerParts []string
 		if len(ps.Paper.Authors) > 0 {
@@ -3,6 +3,7 @@ package publisher
 import (
 	"context"
 	"fmt"
+	"html"
 	"net/smtp"
 	"strings"
 
@@ -31,7 +32,8 @@ func NewEmailPublisher(host string, port int, username, password, from string, t
 }
 
 func (p *EmailPublisher) Publish(_ context.Context, digest *summarizer.Digest) error {
-	subject := fmt.Sprintf("Daily Feed: %s - %s", digest.GetTopicsString(), digest.Date.Format("2006-01-02"))
+	loc := locale(digest)
+	subject := loc.T("title_dated", digest.GetTopicsString(), loc.Date(digest.Date, "date_short"))
 	body := buildHTMLBody(digest)
 
 	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=\"UTF-8\"\r\n\r\n%s",
@@ -52,6 +54,14 @@ func (p *EmailPublisher) Publish(_ context.Context, digest *summarizer.Digest) e
 }
 
 func buildHTMLBody(digest *summarizer.Digest) string {
+	return buildHTMLPage(digest, "")
+}
+
+// buildHTMLPage renders the digest as a complete HTML document. nav is raw
+// HTML placed above and below the digest, used by the web publisher for
+// history navigation.
+func buildHTMLPage(digest *summarizer.Digest, nav string) string {
+	loc := locale(digest)
 	var sb strings.Builder
 
 	sb.WriteString(`<!DOCTYPE html><html><head><style>
@@ -64,21 +74,40 @@ h2 { color: #16213e; }
 .meta { color: #666; font-size: 0.9em; margin-bottom: 10px; }
 .key-points { margin-top: 10px; }
 .key-points li { margin-bottom: 5px; }
+.nav { display: flex; justify-content: space-between; margin: 10px 0; }
+.links { margin-bottom: 10px; }
+.footer { color: #999; font-size: 0.8em; }
+.button { display: inline-block; padding: 2px 10px; margin-right: 6px; border: 1px solid #0f3460; border-radius: 4px; color: #0f3460; text-decoration: none; font-size: 0.85em; }
 </style></head><body>`)
 
-	sb.WriteString(fmt.Sprintf("<h1>Daily Feed: %s</h1>", digest.GetTopicsString()))
-	sb.WriteString(fmt.Sprintf("<p><em>%s</em></p>", digest.Date.Format("January 2, 2006")))
+	sb.WriteString(nav)
+
+	sb.WriteString(fmt.Sprintf("<h1>%s</h1>", loc.T("title", digest.GetTopicsString())))
+	sb.WriteString(fmt.Sprintf("<p><em>%s</em></p>", loc.Date(digest.Date, "date_long")))
 
-	sb.WriteString(fmt.Sprintf(`<div class="overview"><h2>Overview</h2><p>%s</p></div>`, digest.Overview))
+	sb.WriteString(fmt.Sprintf(`<div class="overview"><h2>%s</h2><p>%s</p></div>`, loc.T("overview"), digest.Overview))
 
 	for i, s := range digest.Summaries {
 		sb.WriteString(`<div class="paper">`)
-		sb.WriteString(fmt.Sprintf(`<h3>%d. <a href="%s">%s</a></h3>`, i+1, s.Paper.URL, s.Paper.Title))
+		sb.WriteString(fmt.Sprintf(`<h3>%d. <a href="%s">%s</a></h3>`, i+1, html.EscapeString(s.Paper.URL), s.Paper.Title))
 		sb.WriteString(fmt.Sprintf(`<div class="meta">%s | %s</div>`, strings.Join(s.Paper.Authors, ", "), s.Paper.Category))
+		if notes := paperNotes(loc, s.Paper); len(notes) > 0 {
+			for i, note := range notes {
+				notes[i] = html.EscapeString(note)
+			}
+			sb.WriteString(fmt.Sprintf(`<div class="meta">%s</div>`, strings.Join(notes, " | ")))
+		}
+		if links := paperLinks(loc, s.Paper); len(links) > 0 {
+			sb.WriteString(`<div class="links">`)
+			for _, link := range links {
+				sb.WriteString(fmt.Sprintf(`<a class="button" href="%s">%s</a>`, html.EscapeString(link.URL), html.EscapeString(link.Label)))
+			}
+			sb.WriteString("</div>")
+		}
 		sb.WriteString(fmt.Sprintf("<p>%s</p>", s.Summary))
 
 		if len(s.KeyPoints) > 0 {
-			sb.WriteString(`<div class="key-points"><strong>Key Points:</strong><ul>`)
+			sb.WriteString(fmt.Sprintf(`<div class="key-points"><strong>%s:</strong><ul>`, loc.T("key_points")))
 			for _, kp := range s.KeyPoints {
 				sb.WriteString(fmt.Sprintf("<li>%s</li>", kp))
 			}
@@ -87,6 +116,10 @@ h2 { color: #16213e; }
 		sb.WriteString("</div>")
 	}
 
+	if note := backendNote(digest); note != "" {
+		sb.WriteString(fmt.Sprintf(`<p class="footer">%s</p>`, note))
+	}
+	sb.WriteString(nav)
 	sb.WriteString("</body></html>")
 	return sb.String()
-}
\ No newline at end of file
+}
@@ -0,0 +1,290 @@
+package publisher
+
+import (
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/archive"
+	"github.com/ryosukesatoh/daily-feed/internal/i18n"
+	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
+)
+
+// Feed modes: one entry per paper summary or one entry per digest.
+const (
+	FeedModePaper  = "paper"
+	FeedModeDigest = "digest"
+)
+
+// Atom 1.0 structures
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Authors    []atomAuthor   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+}
+
+// RSS 2.0 structures
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+}
+
+// feedItem is the format-neutral form of a feed entry.
+type feedItem struct {
+	id         string
+	title      string
+	link       string
+	date       time.Time
+	authors    []string
+	categories []string
+	summary    string
+	content    string // HTML
+}
+
+// buildFeedItems turns digests (newest first) into feed items.
+func buildFeedItems(digests []*summarizer.Digest, baseURL, mode string) []feedItem {
+	var items []feedItem
+	for _, d := range digests {
+		loc := locale(d)
+		date := d.Date.Format(archive.DateLayout)
+		digestURL := fmt.Sprintf("%s/digests/%s", baseURL, date)
+
+		if mode == FeedModeDigest {
+			var content strings.Builder
+			content.WriteString(fmt.Sprintf("<p>%s</p>", html.EscapeString(d.Overview)))
+			for i, s := range d.Summaries {
+				content.WriteString(fmt.Sprintf(`<h3>%d. <a href="%s">%s</a></h3>`, i+1, html.EscapeString(s.Paper.URL), html.EscapeString(s.Paper.Title)))
+				content.WriteString(paperContentHTML(loc, s))
+			}
+			if note := backendNote(d); note != "" {
+				content.WriteString(fmt.Sprintf("<p><em>%s</em></p>", html.EscapeString(note)))
+			}
+			items = append(items, feedItem{
+				id:      stableID("digest", date),
+				title:   loc.T("title_dated", d.GetTopicsString(), date),
+				link:    digestURL,
+				date:    d.Date,
+				authors: []string{"Daily Feed"},
+				summary: d.Overview,
+				content: content.String(),
+			})
+			continue
+		}
+
+		for _, s := range d.Summaries {
+			key := s.Paper.ID
+			if key == "" {
+				key = s.Paper.URL
+			}
+			link := s.Paper.URL
+			if link == "" {
+				link = digestURL
+			}
+			var categories []string
+			if s.Paper.Category != "" {
+				categories = append(categories, s.Paper.Category)
+			}
+			items = append(items, feedItem{
+				id:         stableID("paper", date, key),
+				title:      s.Paper.Title,
+				link:       link,
+				date:       d.Date,
+				authors:    s.Paper.Authors,
+				categories: categories,
+				summary:    s.Summary,
+				content:    paperContentHTML(loc, s),
+			})
+		}
+	}
+	return items
+}
+
+// paperContentHTML renders the summary, key points and authors of a paper.
+func paperContentHTML(loc *i18n.Locale, s summarizer.PaperSummary) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<p>%s</p>", html.EscapeString(s.Summary)))
+	if len(s.KeyPoints) > 0 {
+		sb.WriteString(fmt.Sprintf("<p><strong>%s:</strong></p><ul>", html.EscapeString(loc.T("key_points"))))
+		for _, kp := range s.KeyPoints {
+			sb.WriteString(fmt.Sprintf("<li>%s</li>", html.EscapeString(kp)))
+		}
+		sb.WriteString("</ul>")
+	}
+	if len(s.Paper.Authors) > 0 {
+		sb.WriteString(fmt.Sprintf("<p><em>%s: %s</em></p>", html.EscapeString(loc.T("authors")), html.EscapeString(strings.Join(s.Paper.Authors, ", "))))
+	}
+	for _, note := range paperNotes(loc, s.Paper) {
+		sb.WriteString(fmt.Sprintf("<p>%s</p>", html.EscapeString(note)))
+	}
+	if s.Paper.URL != "" {
+		sb.WriteString(fmt.Sprintf(`<p><a href="%s">%s</a></p>`, html.EscapeString(s.Paper.URL), html.EscapeString(s.Paper.URL)))
+	}
+	if links := paperLinks(loc, s.Paper); len(links) > 0 {
+		anchors := make([]string, len(links))
+		for i, link := range links {
+			anchors[i] = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link.URL), html.EscapeString(link.Label))
+		}
+		sb.WriteString(fmt.Sprintf("<p>%s</p>", strings.Join(anchors, " | ")))
+	}
+	return sb.String()
+}
+
+// stableID derives a name-based (version 5 style) UUID URN from parts, so the
+// same paper in the same digest always gets the same entry ID.
+func stableID(parts ...string) string {
+	sum := sha1.Sum([]byte("daily-feed:" + strings.Join(parts, ":")))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// feedTitle returns the title shared by both feed formats.
+func feedTitle(digests []*summarizer.Digest) string {
+	if len(digests) == 0 {
+		return "Daily Feed"
+	}
+	return locale(digests[0]).T("title", digests[0].GetTopicsString())
+}
+
+// buildAtomFeed renders digests (newest first) as an Atom 1.0 document.
+func buildAtomFeed(digests []*summarizer.Digest, baseURL, mode string) ([]byte, error) {
+	updated := time.Unix(0, 0).UTC()
+	if len(digests) > 0 {
+		updated = digests[0].Date
+	}
+
+	feed := atomFeed{
+		Title:   feedTitle(digests),
+		ID:      stableID("feed"),
+		Updated: updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: baseURL + "/feed.atom", Rel: "self", Type: "application/atom+xml"},
+			{Href: baseURL + "/", Rel: "alternate", Type: "text/html"},
+		},
+		Author: atomAuthor{Name: "Daily Feed"},
+	}
+
+	for _, item := range buildFeedItems(digests, baseURL, mode) {
+		entry := atomEntry{
+			Title:     item.title,
+			ID:        item.id,
+			Updated:   item.date.Format(time.RFC3339),
+			Published: item.date.Format(time.RFC3339),
+			Links:     []atomLink{{Href: item.link, Rel: "alternate"}},
+			Summary:   atomText{Type: "text", Body: item.summary},
+			Content:   atomText{Type: "html", Body: item.content},
+		}
+		for _, a := range item.authors {
+			entry.Authors = append(entry.Authors, atomAuthor{Name: a})
+		}
+		for _, c := range item.categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: c})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return marshalFeed(feed)
+}
+
+// buildRSSFeed renders digests (newest first) as an RSS 2.0 document.
+func buildRSSFeed(digests []*summarizer.Digest, baseURL, mode string) ([]byte, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       feedTitle(digests),
+			Link:        baseURL + "/",
+			Description: "Daily digest of important recent papers",
+			AtomLink:    atomLink{Href: baseURL + "/feed.rss", Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if len(digests) > 0 {
+		feed.Channel.LastBuildDate = digests[0].Date.Format(time.RFC1123Z)
+	}
+
+	for _, item := range buildFeedItems(digests, baseURL, mode) {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       item.title,
+			Link:        item.link,
+			Description: item.content,
+			GUID:        rssGUID{IsPermaLink: "false", Value: item.id},
+			PubDate:     item.date.Format(time.RFC1123Z),
+			Categories:  item.categories,
+		})
+	}
+
+	return marshalFeed(feed)
+}
+
+func marshalFeed(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("web: failed to encode feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
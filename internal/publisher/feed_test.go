@@ -0,0 +1,125 @@
+package publisher
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ryosukesatoh/daily-feed/internal/archive"
+	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
+)
+
+func TestBuildAtomFeedPerPaper(t *testing.T) {
+	digest := sampleDigest()
+	digest.Summaries[0].Paper.ID = "2501.00001"
+
+	body, err := buildAtomFeed([]*summarizer.Digest{digest}, "https://feed.example.com", FeedModePaper)
+	if err != nil {
+		t.Fatalf("buildAtomFeed returned error: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		t.Fatalf("Feed is not valid XML: %v\n%s", err, body)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("Expected one entry per paper (2), got %d", len(feed.Entries))
+	}
+
+	e := feed.Entries[0]
+	if e.Title != "Test Paper One" {
+		t.Errorf("Expected entry title 'Test Paper One', got %q", e.Title)
+	}
+	if len(e.Links) == 0 || e.Links[0].Href != "http://example.com/1" {
+		t.Errorf("Expected entry to link to the paper, got %+v", e.Links)
+	}
+	if len(e.Authors) != 2 || e.Authors[0].Name != "Alice" {
+		t.Errorf("Expected paper authors on entry, got %+v", e.Authors)
+	}
+	if e.Summary.Body != "This is a summary of paper one." {
+		t.Errorf("Expected summary text, got %q", e.Summary.Body)
+	}
+	if !strings.Contains(e.Content.Body, "<li>Point A</li>") {
+		t.Errorf("Expected key points in content, got %q", e.Content.Body)
+	}
+	if !strings.HasPrefix(e.ID, "urn:uuid:") {
+		t.Errorf("Expected urn:uuid entry ID, got %q", e.ID)
+	}
+
+	// Entry IDs must not change between renders (e.g. after a restart).
+	again, _ := buildAtomFeed([]*summarizer.Digest{digest}, "https://other.example.com", FeedModePaper)
+	var feed2 atomFeed
+	xml.Unmarshal(again, &feed2)
+	if feed2.Entries[0].ID != e.ID || feed2.ID != feed.ID {
+		t.Errorf("Expected stable IDs, got %q and %q", e.ID, feed2.Entries[0].ID)
+	}
+	if feed.Entries[0].ID == feed.Entries[1].ID {
+		t.Error("Expected distinct IDs for different papers")
+	}
+}
+
+func TestBuildRSSFeedPerDigest(t *testing.T) {
+	body, err := buildRSSFeed([]*summarizer.Digest{sampleDigest()}, "https://feed.example.com", FeedModeDigest)
+	if err != nil {
+		t.Fatalf("buildRSSFeed returned error: %v", err)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		t.Fatalf("Feed is not valid XML: %v\n%s", err, body)
+	}
+	if feed.Version != "2.0" {
+		t.Errorf("Expected RSS version 2.0, got %q", feed.Version)
+	}
+	if len(feed.Channel.Items) != 1 {
+		t.Fatalf("Expected one item per digest, got %d", len(feed.Channel.Items))
+	}
+
+	item := feed.Channel.Items[0]
+	if item.Link != "https://feed.example.com/digests/2025-01-15" {
+		t.Errorf("Expected item to link to the digest page, got %q", item.Link)
+	}
+	if item.GUID.IsPermaLink != "false" || item.GUID.Value == "" {
+		t.Errorf("Expected non-permalink GUID, got %+v", item.GUID)
+	}
+	for _, want := range []string{"Test Paper One", "Test Paper Two", "Point D", "Alice, Bob"} {
+		if !strings.Contains(item.Description, want) {
+			t.Errorf("Expected description to contain %q", want)
+		}
+	}
+	if !strings.Contains(string(body), `<atom:link href="https://feed.example.com/feed.rss" rel="self"`) {
+		t.Errorf("Expected atom:link self reference, got:\n%s", body)
+	}
+}
+
+func TestWebPublisherServesFeeds(t *testing.T) {
+	a, err := archive.Open("")
+	if err != nil {
+		t.Fatalf("archive.Open returned error: %v", err)
+	}
+	wp := NewWebPublisher(":0", a)
+	wp.SetFeed("https://feed.example.com/", FeedModePaper, 10)
+	wp.Publish(context.Background(), sampleDigest())
+
+	for path, contentType := range map[string]string{
+		"/feed.atom": "application/atom+xml",
+		"/feed.rss":  "application/rss+xml",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		wp.server.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: expected 200, got %d", path, rec.Code)
+		}
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), contentType) {
+			t.Errorf("GET %s: expected content type %s, got %q", path, contentType, rec.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(rec.Body.String(), "https://feed.example.com/feed") {
+			t.Errorf("GET %s: expected configured base URL in feed", path)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package publisher
 
 import (
 	"context"
+	"strings"
 
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/i18n"
 	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
 )
 
@@ -10,3 +13,68 @@ import (
 type Publisher interface {
 	Publish(ctx context.Context, digest *summarizer.Digest) error
 }
+
+// locale returns the message catalog of the digest language, which
+// publishers write their headings and labels with.
+func locale(digest *summarizer.Digest) *i18n.Locale {
+	return i18n.Get(digest.Language)
+}
+
+// backendNote returns the footer line naming the summarizer that produced a
+// digest, with the token usage of the run when the digest carries it, or ""
+// for digests that record neither.
+func backendNote(digest *summarizer.Digest) string {
+	loc := locale(digest)
+	var parts []string
+	if digest.Backend != "" {
+		parts = append(parts, loc.T("summarized_by", digest.Backend))
+	}
+	if len(digest.Usage) > 0 {
+		var in, out int
+		for _, u := range digest.Usage {
+			in += u.InputTokens
+			out += u.OutputTokens
+		}
+		parts = append(parts, loc.T("tokens", in, out, summarizer.TotalCost(digest.Usage)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// paperLink is an extra link shown next to a paper, such as its PDF.
+type paperLink struct {
+	Label string
+	URL   string
+}
+
+// paperLinks returns the PDF, DOI and review forum links of a paper, in
+// that order, skipping those it does not have.
+func paperLinks(loc *i18n.Locale, p fetcher.Paper) []paperLink {
+	var links []paperLink
+	if p.PDFURL != "" && p.PDFURL != p.URL {
+		links = append(links, paperLink{Label: "PDF", URL: p.PDFURL})
+	}
+	if doiURL := "https://doi.org/" + p.DOI; p.DOI != "" && doiURL != p.URL {
+		links = append(links, paperLink{Label: "DOI", URL: doiURL})
+	}
+	if p.ForumURL != "" {
+		links = append(links, paperLink{Label: loc.T("forum"), URL: p.ForumURL})
+	}
+	return links
+}
+
+// paperNotes returns one line per piece of publication metadata: the author
+// comment (often "Accepted at ..."), the journal reference and the review
+// outcome.
+func paperNotes(loc *i18n.Locale, p fetcher.Paper) []string {
+	var notes []string
+	if p.Comment != "" {
+		notes = append(notes, loc.T("comments")+": "+p.Comment)
+	}
+	if p.JournalRef != "" {
+		notes = append(notes, loc.T("journal")+": "+p.JournalRef)
+	}
+	if review := p.ReviewInfo(); review != "" {
+		notes = append(notes, loc.T("review")+": "+review)
+	}
+	return notes
+}
@@ -12,7 +12,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ryosukesatoh/daily-feed/internal/archive"
 	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/i18n"
 	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
 )
 
@@ -301,3 +303,214 @@ func TestDiscordPublishWebhookError(t *testing.T) {
 		t.Errorf("Expected 'unexpected status 400' error, got: %v", err)
 	}
 }
+
+func TestWebPublisherServesArchive(t *testing.T) {
+	dir := t.TempDir()
+	a, err := archive.Open(dir)
+	if err != nil {
+		t.Fatalf("archive.Open returned error: %v", err)
+	}
+	wp := NewWebPublisher(":0", a)
+
+	older := sampleDigest()
+	older.Date = time.Date(2025, 1, 14, 8, 0, 0, 0, time.UTC)
+	older.Overview = "Older overview."
+	for _, d := range []*summarizer.Digest{older, sampleDigest()} {
+		if err := wp.Publish(context.Background(), d); err != nil {
+			t.Fatalf("Publish returned error: %v", err)
+		}
+	}
+
+	ts := httptest.NewServer(wp.server.Handler)
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/")
+	if status != http.StatusOK || !strings.Contains(body, "Overview of today's papers") {
+		t.Errorf("Expected latest digest at /, got %d: %s", status, body)
+	}
+	if !strings.Contains(body, `href="/digests/2025-01-14"`) {
+		t.Error("Expected link to previous digest on latest page")
+	}
+
+	status, body = get("/digests")
+	if status != http.StatusOK || !strings.Contains(body, "2025-01-15") || !strings.Contains(body, "2025-01-14") {
+		t.Errorf("Expected archive index listing both dates, got %d: %s", status, body)
+	}
+
+	status, body = get("/digests/2025-01-14")
+	if status != http.StatusOK || !strings.Contains(body, "Older overview.") {
+		t.Errorf("Expected older digest page, got %d: %s", status, body)
+	}
+	if !strings.Contains(body, `href="/digests/2025-01-15"`) {
+		t.Error("Expected link to next digest on older page")
+	}
+
+	if status, _ = get("/digests/2024-12-31"); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown date, got %d", status)
+	}
+
+	// A new publisher over the same archive directory sees the history immediately.
+	reopened, err := archive.Open(dir)
+	if err != nil {
+		t.Fatalf("archive.Open returned error: %v", err)
+	}
+	if latest := reopened.Latest(); latest == nil || latest.Overview != sampleDigest().Overview {
+		t.Errorf("Expected archive to reload latest digest, got %+v", latest)
+	}
+}
+
+func reviewedDigest() *summarizer.Digest {
+	digest := sampleDigest()
+	digest.Summaries[0].Paper.Decision = "Accept (poster)"
+	digest.Summaries[0].Paper.ReviewScores = []float64{6, 8}
+	digest.Summaries[0].Paper.ForumURL = "https://openreview.net/forum?id=abc"
+	return digest
+}
+
+func TestBuildEmbedsReviewMetadata(t *testing.T) {
+	embeds := (&DiscordPublisher{}).buildEmbeds(reviewedDigest())
+
+	fields := embeds[1].Fields
+	if len(fields) != 3 || fields[1].Name != "Notes" || fields[2].Name != "Links" {
+		t.Fatalf("Expected Key Points, Notes and Links fields, got %+v", fields)
+	}
+	if fields[1].Value != "Review: Accept (poster) · scores 6, 8 (avg 7.0)" {
+		t.Errorf("Unexpected notes field: %q", fields[1].Value)
+	}
+	if fields[2].Value != "[Forum](https://openreview.net/forum?id=abc)" {
+		t.Errorf("Unexpected links field: %q", fields[2].Value)
+	}
+	if len(embeds[2].Fields) != 1 {
+		t.Errorf("Expected no extra fields for a paper without review metadata, got %+v", embeds[2].Fields)
+	}
+}
+
+func TestBuildHTMLBodyReviewMetadata(t *testing.T) {
+	body := buildHTMLBody(reviewedDigest())
+
+	if !strings.Contains(body, "Accept (poster) · scores 6, 8 (avg 7.0)") {
+		t.Error("Expected decision and scores in HTML body")
+	}
+	if !strings.Contains(body, `<a class="button" href="https://openreview.net/forum?id=abc">Forum</a>`) {
+		t.Error("Expected forum link in HTML body")
+	}
+}
+
+func TestPaperLinksAndNotes(t *testing.T) {
+	p := fetcher.Paper{
+		URL:        "http://arxiv.org/abs/2501.01234v3",
+		PDFURL:     "http://arxiv.org/pdf/2501.01234v3",
+		DOI:        "10.1000/example.123",
+		Comment:    "Accepted at CVPR 2025",
+		JournalRef: "Proc. CVPR 2025",
+	}
+
+	en := i18n.Get("en")
+	links := paperLinks(en, p)
+	if len(links) != 2 || links[0].Label != "PDF" || links[1].URL != "https://doi.org/10.1000/example.123" {
+		t.Errorf("Unexpected links: %+v", links)
+	}
+	notes := paperNotes(en, p)
+	if len(notes) != 2 || notes[0] != "Comments: Accepted at CVPR 2025" || notes[1] != "Journal: Proc. CVPR 2025" {
+		t.Errorf("Unexpected notes: %v", notes)
+	}
+
+	// A DOI that is already the main link is not repeated.
+	if links := paperLinks(en, fetcher.Paper{URL: "https://doi.org/10.1101/x", DOI: "10.1101/x"}); len(links) != 0 {
+		t.Errorf("Expected no duplicate DOI link, got %+v", links)
+	}
+}
+
+func TestFootersNameBackend(t *testing.T) {
+	digest := sampleDigest()
+	digest.Backend = "openai/gpt-4o-mini"
+
+	if body := buildHTMLBody(digest); !strings.Contains(body, `<p class="footer">Summarized by openai/gpt-4o-mini</p>`) {
+		t.Error("Expected backend footer in HTML body")
+	}
+	if got := (&DiscordPublisher{}).buildEmbeds(digest)[0].Footer.Text; got != "2025-01-15 | Summarized by openai/gpt-4o-mini" {
+		t.Errorf("Unexpected overview embed footer: %q", got)
+	}
+	items := buildFeedItems([]*summarizer.Digest{digest}, "http://example.com", FeedModeDigest)
+	if !strings.Contains(items[0].content, "Summarized by openai/gpt-4o-mini") {
+		t.Error("Expected backend footer in digest feed entry")
+	}
+
+	// Digests archived before the backend was recorded have no footer.
+	if body := buildHTMLBody(sampleDigest()); strings.Contains(body, "Summarized by") {
+		t.Error("Expected no backend footer for a digest without backend")
+	}
+}
+
+func TestFooterShowsUsage(t *testing.T) {
+	digest := sampleDigest()
+	digest.Backend = "anthropic/claude-3-5-haiku-20241022"
+	digest.Usage = []summarizer.Usage{
+		{Backend: "anthropic/claude-sonnet-4-20250514", Calls: 1, InputTokens: 9000, OutputTokens: 500, CostUSD: 0.0345},
+		{Backend: "anthropic/claude-3-5-haiku-20241022", Calls: 1, InputTokens: 9000, OutputTokens: 1200, CostUSD: 0.012},
+	}
+
+	want := "Summarized by anthropic/claude-3-5-haiku-20241022 · 18000 input + 1700 output tokens ($0.05)"
+	if got := backendNote(digest); got != want {
+		t.Errorf("Expected footer %q, got %q", want, got)
+	}
+	if body := buildHTMLBody(digest); !strings.Contains(body, want) {
+		t.Error("Expected usage in the HTML footer")
+	}
+}
+
+func TestChromeFollowsDigestLanguage(t *testing.T) {
+	digest := reviewedDigest()
+	digest.Language = "de"
+	digest.Backend = "ollama/llama3"
+
+	body := buildHTMLBody(digest)
+	for _, want := range []string{"<h2>Überblick</h2>", "<strong>Kernpunkte:</strong>", "Begutachtung: Accept (poster)", "15. Januar 2025", "Zusammengefasst von ollama/llama3"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in the German HTML body", want)
+		}
+	}
+	if strings.Contains(body, "Key Points") {
+		t.Error("Expected no English headings in the German HTML body")
+	}
+
+	embeds := (&DiscordPublisher{}).buildEmbeds(digest)
+	if got := embeds[0].Footer.Text; got != "15.01.2025 | Zusammengefasst von ollama/llama3" {
+		t.Errorf("Unexpected overview embed footer: %q", got)
+	}
+	if got := embeds[1].Fields[0].Name; got != "Kernpunkte" {
+		t.Errorf("Expected localized key points field, got %q", got)
+	}
+
+	items := buildFeedItems([]*summarizer.Digest{digest}, "http://example.com", FeedModePaper)
+	if !strings.Contains(items[0].content, "<strong>Kernpunkte:</strong>") {
+		t.Errorf("Expected localized key points in the feed entry, got %s", items[0].content)
+	}
+}
+
+func TestBuildHTMLBodyEscapesPaperMetadata(t *testing.T) {
+	digest := sampleDigest()
+	digest.Summaries[0].Paper.Comment = `Accepted at <ICML> & "best paper" track`
+	digest.Summaries[0].Paper.PDFURL = `http://arxiv.org/pdf/2501.01234"onclick="x`
+
+	body := buildHTMLBody(digest)
+	if !strings.Contains(body, "Comments: Accepted at &lt;ICML&gt; &amp; &#34;best paper&#34; track") {
+		t.Error("Expected the comment to be escaped")
+	}
+	if !strings.Contains(body, `href="http://arxiv.org/pdf/2501.01234&#34;onclick=&#34;x"`) {
+		t.Error("Expected the PDF URL to be escaped in its href")
+	}
+	if strings.Contains(body, "<ICML>") || strings.Contains(body, `"onclick="`) {
+		t.Error("Expected no raw metadata in the HTML body")
+	}
+}
@@ -16,27 +16,34 @@ func NewStdoutPublisher() *StdoutPublisher {
 }
 
 func (p *StdoutPublisher) Publish(_ context.Context, digest *summarizer.Digest) error {
+	loc := locale(digest)
 	fmt.Println(strings.Repeat("=", 72))
-	fmt.Printf("Daily Feed Digest: %s\n", digest.GetTopicsString())
-	fmt.Printf("Date: %s\n", digest.Date.Format("2006-01-02 15:04"))
+	fmt.Println(loc.T("digest_title", digest.GetTopicsString()))
+	fmt.Printf("%s: %s\n", loc.T("date"), loc.Date(digest.Date, "date_time"))
 	fmt.Println(strings.Repeat("=", 72))
 	fmt.Println()
 
-	fmt.Println("Overview:")
+	fmt.Printf("%s:\n", loc.T("overview"))
 	fmt.Println(digest.Overview)
 	fmt.Println()
 
 	for i, s := range digest.Summaries {
 		fmt.Println(strings.Repeat("-", 72))
 		fmt.Printf("%d. %s\n", i+1, s.Paper.Title)
-		fmt.Printf("   Authors: %s\n", strings.Join(s.Paper.Authors, ", "))
-		fmt.Printf("   URL: %s\n", s.Paper.URL)
-		fmt.Printf("   Category: %s\n", s.Paper.Category)
+		fmt.Printf("   %s: %s\n", loc.T("authors"), strings.Join(s.Paper.Authors, ", "))
+		fmt.Printf("   %s: %s\n", loc.T("url"), s.Paper.URL)
+		fmt.Printf("   %s: %s\n", loc.T("category"), s.Paper.Category)
+		for _, note := range paperNotes(loc, s.Paper) {
+			fmt.Printf("   %s\n", note)
+		}
+		for _, link := range paperLinks(loc, s.Paper) {
+			fmt.Printf("   %s: %s\n", link.Label, link.URL)
+		}
 		fmt.Println()
 		fmt.Printf("   %s\n", s.Summary)
 		fmt.Println()
 		if len(s.KeyPoints) > 0 {
-			fmt.Println("   Key Points:")
+			fmt.Printf("   %s:\n", loc.T("key_points"))
 			for _, kp := range s.KeyPoints {
 				fmt.Printf("   - %s\n", kp)
 			}
@@ -44,6 +51,9 @@ func (p *StdoutPublisher) Publish(_ context.Context, digest *summarizer.Digest)
 		fmt.Println()
 	}
 
+	if note := backendNote(digest); note != "" {
+		fmt.Println(note)
+	}
 	fmt.Println(strings.Repeat("=", 72))
 	return nil
 }
\ No newline at end of file
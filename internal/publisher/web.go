@@ -3,26 +3,48 @@ package publisher
 import (
 	"context"
 	"fmt"
+	"html"
 	"log"
 	"net"
 	"net/http"
-	"sync"
+	"strings"
 
+	"github.com/ryosukesatoh/daily-feed/internal/archive"
+	"github.com/ryosukesatoh/daily-feed/internal/runlog"
 	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
 )
 
-// WebPublisher serves the latest digest as an HTML page over HTTP.
+// WebPublisher serves published digests as HTML pages over HTTP. The latest
+// digest is served at /, the history at /digests and each day at /digests/{date}.
+// Recent digests are also available as feeds at /feed.atom and /feed.rss, and
+// as JSON under /api/v1.
 type WebPublisher struct {
-	addr   string
-	server *http.Server
-	mu     sync.RWMutex
-	latest *summarizer.Digest
+	addr      string
+	server    *http.Server
+	archive   *archive.Archive
+	baseURL   string // Absolute URL the server is reachable at, used in feeds
+	feedMode  string // FeedModePaper or FeedModeDigest
+	feedLimit int    // Number of recent digests included in feeds
+	runLog    *runlog.Log
 }
 
-func NewWebPublisher(addr string) *WebPublisher {
-	wp := &WebPublisher{addr: addr}
+// NewWebPublisher creates a WebPublisher that stores digests in a. Because
+// the archive is loaded when it is opened, pages are available immediately
+// after a restart.
+func NewWebPublisher(addr string, a *archive.Archive) *WebPublisher {
+	wp := &WebPublisher{
+		addr:      addr,
+		archive:   a,
+		feedMode:  FeedModeDigest,
+		feedLimit: 20,
+	}
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", wp.handleIndex)
+	mux.HandleFunc("GET /digests", wp.handleHistory)
+	mux.HandleFunc("GET /digests/{date}", wp.handleDigest)
+	mux.HandleFunc("GET /feed.atom", wp.handleAtom)
+	mux.HandleFunc("GET /feed.rss", wp.handleRSS)
+	wp.registerAPI(mux)
 	wp.server = &http.Server{
 		Addr:    addr,
 		Handler: mux,
@@ -30,6 +52,19 @@ func NewWebPublisher(addr string) *WebPublisher {
 	return wp
 }
 
+// SetFeed configures the Atom/RSS feeds. baseURL is the public address of the
+// server (derived from the request when empty), mode is FeedModePaper or
+// FeedModeDigest and limit is the number of recent digests to include.
+func (wp *WebPublisher) SetFeed(baseURL, mode string, limit int) {
+	wp.baseURL = strings.TrimSuffix(baseURL, "/")
+	if mode != "" {
+		wp.feedMode = mode
+	}
+	if limit > 0 {
+		wp.feedLimit = limit
+	}
+}
+
 // Start begins serving HTTP in the background. Call Shutdown to stop.
 func (wp *WebPublisher) Start() error {
 	ln, err := net.Listen("tcp", wp.addr)
@@ -51,17 +86,20 @@ func (wp *WebPublisher) Shutdown(ctx context.Context) error {
 }
 
 func (wp *WebPublisher) Publish(_ context.Context, digest *summarizer.Digest) error {
-	wp.mu.Lock()
-	wp.latest = digest
-	wp.mu.Unlock()
+	if err := wp.archive.Add(digest); err != nil {
+		return fmt.Errorf("web: %w", err)
+	}
 	log.Printf("Web publisher updated with new digest for %q", digest.GetTopicsString())
 	return nil
 }
 
 func (wp *WebPublisher) handleIndex(w http.ResponseWriter, r *http.Request) {
-	wp.mu.RLock()
-	digest := wp.latest
-	wp.mu.RUnlock()
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	digest := wp.archive.Latest()
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
@@ -70,5 +108,105 @@ func (wp *WebPublisher) handleIndex(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Fprint(w, buildHTMLBody(digest))
-}
\ No newline at end of file
+	fmt.Fprint(w, wp.renderDigest(digest))
+}
+
+func (wp *WebPublisher) handleDigest(w http.ResponseWriter, r *http.Request) {
+	digest, ok := wp.archive.Get(r.PathValue("date"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, wp.renderDigest(digest))
+}
+
+func (wp *WebPublisher) handleHistory(w http.ResponseWriter, r *http.Request) {
+	var sb strings.Builder
+	sb.WriteString(`<!DOCTYPE html><html><head><style>
+body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 700px; margin: 0 auto; padding: 20px; color: #333; }
+h1 { color: #1a1a2e; border-bottom: 2px solid #e94560; padding-bottom: 10px; }
+li { margin-bottom: 8px; }
+.meta { color: #666; font-size: 0.9em; }
+</style></head><body><h1>Daily Feed Archive</h1>`)
+
+	dates := wp.archive.Dates()
+	if len(dates) == 0 {
+		sb.WriteString("<p>No digests published yet.</p>")
+	} else {
+		sb.WriteString("<ul>")
+		for _, date := range dates {
+			digest, ok := wp.archive.Get(date)
+			if !ok {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf(`<li><a href="/digests/%s">%s</a> <span class="meta">%s &middot; %d papers</span></li>`,
+				date, date, html.EscapeString(digest.GetTopicsString()), len(digest.Summaries)))
+		}
+		sb.WriteString("</ul>")
+	}
+	sb.WriteString(`<p><a href="/">Latest digest</a></p></body></html>`)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, sb.String())
+}
+
+// renderDigest renders a digest page with links to the neighbouring days and the archive index.
+func (wp *WebPublisher) renderDigest(digest *summarizer.Digest) string {
+	prev, next := wp.archive.Neighbors(digest.Date.Format(archive.DateLayout))
+
+	var nav strings.Builder
+	nav.WriteString(`<div class="nav"><span>`)
+	if prev != "" {
+		nav.WriteString(fmt.Sprintf(`<a href="/digests/%s">&larr; %s</a>`, prev, prev))
+	}
+	nav.WriteString(`</span><a href="/digests">Archive</a><span>`)
+	if next != "" {
+		nav.WriteString(fmt.Sprintf(`<a href="/digests/%s">%s &rarr;</a>`, next, next))
+	}
+	nav.WriteString(`</span></div>`)
+
+	return buildHTMLPage(digest, nav.String())
+}
+
+func (wp *WebPublisher) handleAtom(w http.ResponseWriter, r *http.Request) {
+	body, err := buildAtomFeed(wp.recentDigests(), wp.feedBaseURL(r), wp.feedMode)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write(body)
+}
+
+func (wp *WebPublisher) handleRSS(w http.ResponseWriter, r *http.Request) {
+	body, err := buildRSSFeed(wp.recentDigests(), wp.feedBaseURL(r), wp.feedMode)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Write(body)
+}
+
+// recentDigests returns up to feedLimit digests, newest first.
+func (wp *WebPublisher) recentDigests() []*summarizer.Digest {
+	digests := wp.allDigests()
+	if len(digests) > wp.feedLimit {
+		digests = digests[:wp.feedLimit]
+	}
+	return digests
+}
+
+// feedBaseURL returns the configured base URL, falling back to the address the request was sent to.
+func (wp *WebPublisher) feedBaseURL(r *http.Request) string {
+	if wp.baseURL != "" {
+		return wp.baseURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
@@ -0,0 +1,129 @@
+package runlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
+)
+
+// Run statuses.
+const (
+	StatusSuccess = "success" // Every publisher succeeded
+	StatusPartial = "partial" // Some publishers failed
+	StatusFailed  = "failed"  // The run returned an error
+	StatusSkipped = "skipped" // The monthly budget was spent
+)
+
+// Run is the record of a single pipeline run.
+type Run struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Topics     []string  `json:"topics"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Fetched    int       `json:"fetched"`    // Papers returned by the fetcher
+	Candidates int       `json:"candidates"` // Papers passed to the summarizer
+	Summarized int       `json:"summarized"` // Papers in the digest
+	Published  int       `json:"published"`  // Publishers that succeeded
+	Failed     int       `json:"failed"`     // Publishers that failed
+
+	Usage   []summarizer.Usage `json:"usage,omitempty"` // Token usage per summarizer backend
+	CostUSD float64            `json:"cost_usd"`        // Total cost of the usage
+}
+
+// Log appends run records to a JSON Lines file.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Open returns a Log writing to path. The file is created on the first Append.
+func Open(path string) (*Log, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("runlog: failed to create %s: %w", dir, err)
+		}
+	}
+	return &Log{path: path}, nil
+}
+
+// Append adds a run record to the end of the log.
+func (l *Log) Append(run Run) error {
+	line, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("runlog: failed to encode run: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("runlog: failed to open %s: %w", l.path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("runlog: failed to write %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// List returns all recorded runs, newest first.
+func (l *Log) List() ([]Run, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Run{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("runlog: failed to open %s: %w", l.path, err)
+	}
+	defer f.Close()
+
+	runs := []Run{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("runlog: failed to parse %s: %w", l.path, err)
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("runlog: failed to read %s: %w", l.path, err)
+	}
+
+	for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+		runs[i], runs[j] = runs[j], runs[i]
+	}
+	return runs, nil
+}
+
+// CostSince adds up the cost of the runs started at or after t.
+func (l *Log) CostSince(t time.Time) (float64, error) {
+	runs, err := l.List()
+	if err != nil {
+		return 0, err
+	}
+	var total float64
+	for _, run := range runs {
+		if !run.StartedAt.Before(t) {
+			total += run.CostUSD
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,68 @@
+package runlog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLogAppendAndList(t *testing.T) {
+	l, err := Open(filepath.Join(t.TempDir(), "runs", "runs.jsonl"))
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	runs, err := l.List()
+	if err != nil {
+		t.Fatalf("List on empty log returned error: %v", err)
+	}
+	if len(runs) != 0 {
+		t.Fatalf("Expected no runs, got %d", len(runs))
+	}
+
+	first := Run{StartedAt: time.Date(2025, 1, 14, 8, 0, 0, 0, time.UTC), Status: StatusSuccess, Fetched: 20}
+	second := Run{StartedAt: time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC), Status: StatusFailed, Error: "fetch failed"}
+	for _, r := range []Run{first, second} {
+		if err := l.Append(r); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	runs, err = l.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 runs, got %d", len(runs))
+	}
+	if runs[0].Status != StatusFailed || runs[0].Error != "fetch failed" {
+		t.Errorf("Expected newest run first, got %+v", runs[0])
+	}
+	if runs[1].Fetched != 20 {
+		t.Errorf("Expected fetched count to round-trip, got %d", runs[1].Fetched)
+	}
+}
+
+func TestLogCostSince(t *testing.T) {
+	l, err := Open(filepath.Join(t.TempDir(), "runs.jsonl"))
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	for _, r := range []Run{
+		{StartedAt: time.Date(2025, 1, 31, 8, 0, 0, 0, time.UTC), CostUSD: 1.5},
+		{StartedAt: time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC), CostUSD: 0.25},
+		{StartedAt: time.Date(2025, 2, 2, 8, 0, 0, 0, time.UTC), CostUSD: 0.5},
+	} {
+		if err := l.Append(r); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	cost, err := l.CostSince(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CostSince returned error: %v", err)
+	}
+	if cost != 0.75 {
+		t.Errorf("Expected $0.75 since February 1, got $%v", cost)
+	}
+}
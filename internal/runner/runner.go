@@ -2,15 +2,25 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
 	"github.com/ryosukesatoh/daily-feed/internal/publisher"
+	"github.com/ryosukesatoh/daily-feed/internal/runlog"
+	"github.com/ryosukesatoh/daily-feed/internal/state"
 	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
 )
 
+// Enricher adds information to the fetched papers before they are summarized,
+// such as full-text excerpts. It must not fail the run.
+type Enricher interface {
+	Enrich(ctx context.Context, papers []fetcher.Paper) []fetcher.Paper
+}
+
 // Runner orchestrates the fetch -> summarize -> publish pipeline.
 type Runner struct {
 	topic      string   // Legacy single topic for backward compatibility
@@ -19,8 +29,24 @@ type Runner struct {
 	fetcher    fetcher.Fetcher
 	summarizer summarizer.Summarizer
 	publishers []publisher.Publisher
+	store      state.Store   // Optional seen-paper store
+	retention  time.Duration // How long the store remembers papers
+	runLog     *runlog.Log   // Optional record of past runs
+	// incremental makes the runner fetch everything published since the
+	// last successful run instead of the newest maxResults papers.
+	incremental bool
+	enricher    Enricher // Optional stage between filtering and summarizing
+
+	prices     map[string]summarizer.Price // Token prices by backend or model
+	showUsage  bool                        // Add the token usage to the digest
+	budget     float64                     // Monthly budget in USD; 0 means no cap
+	overBudget summarizer.Summarizer       // Used once the budget is spent; nil skips runs
 }
 
+// errBudgetSpent is returned by run when the monthly budget is spent and no
+// cheaper summarizer is configured.
+var errBudgetSpent = errors.New("runner: monthly budget spent, skipping the run")
+
 func New(topic string, maxResults int, f fetcher.Fetcher, s summarizer.Summarizer, pubs []publisher.Publisher) *Runner {
 	return &Runner{
 		topic:      topic,
@@ -38,7 +64,7 @@ func NewMultiTopic(topics []string, maxResults int, f fetcher.Fetcher, s summari
 	if len(topics) > 0 {
 		topic = topics[0]
 	}
-	
+
 	return &Runner{
 		topic:      topic,
 		topics:     topics,
@@ -49,6 +75,27 @@ func NewMultiTopic(topics []string, maxResults int, f fetcher.Fetcher, s summari
 	}
 }
 
+// SetStore enables the seen-paper store. Papers already published in an earlier
+// run are dropped before summarizing, and papers not seen for longer than
+// retention are forgotten. A zero retention keeps papers forever.
+func (r *Runner) SetStore(store state.Store, retention time.Duration) {
+	r.store = store
+	r.retention = retention
+}
+
+// SetIncremental enables "since last run" fetching. It takes effect only when
+// a store is set and the fetcher implements fetcher.SinceFetcher; the first
+// run, with no high-water mark yet, fetches the newest maxResults papers.
+func (r *Runner) SetIncremental(enabled bool) {
+	r.incremental = enabled
+}
+
+// SetEnricher adds an enrichment stage that runs on the candidate papers
+// before they are passed to the summarizer.
+func (r *Runner) SetEnricher(e Enricher) {
+	r.enricher = e
+}
+
 // GetTopics returns the topics, prioritizing the new topics field over the legacy topic field.
 func (r *Runner) GetTopics() []string {
 	if len(r.topics) > 0 {
@@ -65,36 +112,121 @@ func (r *Runner) GetTopicsString() string {
 	return strings.Join(r.GetTopics(), ", ")
 }
 
+// SetRunLog makes the runner append a record of every run to l.
+func (r *Runner) SetRunLog(l *runlog.Log) {
+	r.runLog = l
+}
+
+// SetPrices sets the token prices, in USD per million tokens, keyed by
+// backend (e.g. "openai/gpt-4o") or model name. With showInDigest, the token
+// usage of the run is added to the digest and shown in publisher footers.
+func (r *Runner) SetPrices(prices map[string]summarizer.Price, showInDigest bool) {
+	r.prices = prices
+	r.showUsage = showInDigest
+}
+
+// SetBudget caps the monthly cost of the runs recorded in the run log. Once
+// the runs started this calendar month have cost monthlyUSD, later runs are
+// summarized by fallback, or skipped when fallback is nil. It has no effect
+// without a run log.
+func (r *Runner) SetBudget(monthlyUSD float64, fallback summarizer.Summarizer) {
+	r.budget = monthlyUSD
+	r.overBudget = fallback
+}
+
 // Run executes the full pipeline once.
 func (r *Runner) Run(ctx context.Context) error {
+	rec := runlog.Run{StartedAt: time.Now(), Topics: r.GetTopics()}
+	meter := summarizer.NewMeter(r.prices)
+	err := r.run(summarizer.WithMeter(ctx, meter), &rec, meter)
+
+	rec.Usage = meter.Usage()
+	rec.CostUSD = summarizer.TotalCost(rec.Usage)
+	for _, u := range rec.Usage {
+		log.Printf("Token usage of %s", u)
+	}
+
+	r.recordRun(rec, err)
+	if errors.Is(err, errBudgetSpent) {
+		log.Println(err)
+		return nil
+	}
+	return err
+}
+
+// run executes the pipeline, filling in the counts of rec as it goes. API
+// calls made while summarizing are recorded by meter.
+func (r *Runner) run(ctx context.Context, rec *runlog.Run, meter *summarizer.Meter) error {
 	topics := r.GetTopics()
 	topicsString := r.GetTopicsString()
-	
+
+	s, err := r.budgetSummarizer(rec.StartedAt)
+	if err != nil {
+		return err
+	}
+
 	log.Printf("Starting pipeline for topic(s) %q (max_results=%d)", topicsString, r.maxResults)
 
 	// Step 1: Fetch papers
 	log.Println("Fetching papers...")
 	var papers []fetcher.Paper
-	var err error
-	
-	if len(topics) == 1 {
+
+	since, sinceFetcher, err := r.highWater()
+	if err != nil {
+		return fmt.Errorf("runner: state store failed: %w", err)
+	}
+
+	var until time.Time
+	switch {
+	case sinceFetcher != nil && !since.IsZero():
+		log.Printf("Fetching papers published since %s", since.Format(time.RFC3339))
+		papers, until, err = sinceFetcher.FetchSince(ctx, topics, since)
+	case len(topics) == 1:
 		papers, err = r.fetcher.Fetch(ctx, topics[0], r.maxResults)
-	} else {
+	default:
 		papers, err = r.fetcher.FetchMultiple(ctx, topics, r.maxResults)
 	}
-	
+
 	if err != nil {
 		return fmt.Errorf("runner: fetch failed: %w", err)
 	}
 	log.Printf("Fetched %d papers", len(papers))
+	rec.Fetched = len(papers)
+	fetched := papers
+
+	if r.store != nil {
+		papers, err = r.filterSeen(papers)
+		if err != nil {
+			return fmt.Errorf("runner: state store failed: %w", err)
+		}
+	}
+
+	if r.enricher != nil && len(papers) > 0 {
+		log.Println("Enriching papers...")
+		papers = r.enricher.Enrich(ctx, papers)
+	}
 
 	// Step 2: Summarize
 	log.Println("Summarizing papers...")
-	digest, err := r.summarizer.Summarize(ctx, papers)
+	rec.Candidates = len(papers)
+	digest, err := s.Summarize(ctx, papers)
 	if err != nil {
 		return fmt.Errorf("runner: summarize failed: %w", err)
 	}
 	log.Printf("Generated digest with %d summaries", len(digest.Summaries))
+	if digest.PromptVersion != "" {
+		log.Printf("Digest produced by %s with prompt version %s", digest.Backend, digest.PromptVersion)
+	}
+	rec.Summarized = len(digest.Summaries)
+	if r.showUsage {
+		digest.Usage = meter.Usage()
+	}
+
+	digestPapers := make([]fetcher.Paper, len(digest.Summaries))
+	for i, ps := range digest.Summaries {
+		digestPapers[i] = ps.Paper
+	}
+	r.markState(state.StageSummarized, digestPapers)
 
 	// Step 3: Publish - Continue with other publishers even if one fails
 	var publishErrors []error
@@ -109,17 +241,151 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	}
 
+	rec.Failed = len(publishErrors)
+	rec.Published = len(r.publishers) - len(publishErrors)
+
 	// If all publishers failed, return an error
 	if len(publishErrors) == len(r.publishers) && len(r.publishers) > 0 {
 		return fmt.Errorf("runner: all publishers failed: %v", publishErrors)
 	}
 
+	r.markState(state.StagePublished, digestPapers)
+	if sinceFetcher != nil {
+		r.advanceHighWater(since, until, fetched)
+	}
+
 	// If some publishers succeeded, log the failures but don't fail the pipeline
 	if len(publishErrors) > 0 {
 		log.Printf("Pipeline completed with %d publisher failures out of %d publishers", len(publishErrors), len(r.publishers))
 	} else {
 		log.Println("Pipeline completed successfully")
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
+
+// budgetSummarizer returns the summarizer for a run started at now: the
+// configured one while the monthly budget lasts, then the fallback, or
+// errBudgetSpent when there is none.
+func (r *Runner) budgetSummarizer(now time.Time) (summarizer.Summarizer, error) {
+	if r.budget <= 0 || r.runLog == nil {
+		return r.summarizer, nil
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	spent, err := r.runLog.CostSince(monthStart)
+	if err != nil {
+		return nil, fmt.Errorf("runner: failed to read monthly cost: %w", err)
+	}
+	if spent < r.budget {
+		return r.summarizer, nil
+	}
+
+	if r.overBudget == nil {
+		return nil, errBudgetSpent
+	}
+	log.Printf("Monthly budget spent ($%.2f of $%.2f), falling back to the over-budget summarizer", spent, r.budget)
+	return r.overBudget, nil
+}
+
+// filterSeen records the fetched papers in the store, forgets papers older than
+// the retention window and drops papers that were already published.
+func (r *Runner) filterSeen(papers []fetcher.Paper) ([]fetcher.Paper, error) {
+	now := time.Now()
+	if r.retention > 0 {
+		if err := r.store.Prune(now.Add(-r.retention)); err != nil {
+			return nil, err
+		}
+	}
+
+	fresh, err := state.FilterPublished(r.store, papers)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.Mark(state.StageFetched, state.PaperIDs(papers), now); err != nil {
+		return nil, err
+	}
+
+	if skipped := len(papers) - len(fresh); skipped > 0 {
+		log.Printf("Skipped %d already published papers, %d remaining", skipped, len(fresh))
+	}
+	return fresh, nil
+}
+
+// highWater returns the high-water mark and the fetcher to page with when
+// incremental fetching is in effect, and a nil fetcher otherwise.
+func (r *Runner) highWater() (time.Time, fetcher.SinceFetcher, error) {
+	sf, ok := r.fetcher.(fetcher.SinceFetcher)
+	if !r.incremental || r.store == nil || !ok {
+		return time.Time{}, nil, nil
+	}
+	since, err := r.store.HighWater(r.highWaterKey())
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return since, sf, nil
+}
+
+// highWaterKey identifies the topic set, so changing the topics starts afresh.
+func (r *Runner) highWaterKey() string {
+	return "topics:" + strings.Join(r.GetTopics(), "|")
+}
+
+// advanceHighWater moves the high-water mark to the newest paper fetched in
+// this run, or to until when the fetcher stopped early, so that the papers it
+// did not reach are fetched next time. It is only called once the digest was
+// published, so a failed run is retried over the same window.
+func (r *Runner) advanceHighWater(since, until time.Time, papers []fetcher.Paper) {
+	newest := since
+	for _, p := range papers {
+		if p.Published.After(newest) {
+			newest = p.Published
+		}
+	}
+	if !until.IsZero() && newest.After(until) {
+		newest = until
+	}
+	if newest.Equal(since) {
+		return
+	}
+	if err := r.store.SetHighWater(r.highWaterKey(), newest); err != nil {
+		log.Printf("WARNING: failed to record high-water mark: %v", err)
+		return
+	}
+	log.Printf("Recorded high-water mark %s", newest.Format(time.RFC3339))
+}
+
+// markState records that papers reached stage. Failures are logged rather than
+// returned because the digest has already been produced at this point.
+func (r *Runner) markState(stage state.Stage, papers []fetcher.Paper) {
+	if r.store == nil {
+		return
+	}
+	if err := r.store.Mark(stage, state.PaperIDs(papers), time.Now()); err != nil {
+		log.Printf("WARNING: failed to record %s papers: %v", stage, err)
+	}
+}
+
+// recordRun appends the finished run to the run log, if one is configured.
+func (r *Runner) recordRun(rec runlog.Run, err error) {
+	if r.runLog == nil {
+		return
+	}
+
+	rec.FinishedAt = time.Now()
+	switch {
+	case errors.Is(err, errBudgetSpent):
+		rec.Status = runlog.StatusSkipped
+	case err != nil:
+		rec.Status = runlog.StatusFailed
+		rec.Error = err.Error()
+	case rec.Failed > 0:
+		rec.Status = runlog.StatusPartial
+	default:
+		rec.Status = runlog.StatusSuccess
+	}
+
+	if err := r.runLog.Append(rec); err != nil {
+		log.Printf("WARNING: failed to record run: %v", err)
+	}
+}
@@ -3,11 +3,15 @@ package runner
 import (
 	"context"
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
 	"github.com/ryosukesatoh/daily-feed/internal/publisher"
+	"github.com/ryosukesatoh/daily-feed/internal/runlog"
+	"github.com/ryosukesatoh/daily-feed/internal/state"
 	"github.com/ryosukesatoh/daily-feed/internal/summarizer"
 )
 
@@ -27,24 +31,54 @@ func (m *mockFetcher) FetchMultiple(ctx context.Context, topics []string, maxRes
 }
 
 type mockSummarizer struct {
-	digest *summarizer.Digest
-	err    error
+	digest   *summarizer.Digest
+	err      error
+	received []fetcher.Paper
 }
 
 func (m *mockSummarizer) Summarize(ctx context.Context, papers []fetcher.Paper) (*summarizer.Digest, error) {
+	m.received = papers
 	return m.digest, m.err
 }
 
+// meteredSummarizer reports one API call per Summarize.
+type meteredSummarizer struct {
+	backend       string
+	input, output int
+	calls         int
+}
+
+func (m *meteredSummarizer) Summarize(ctx context.Context, papers []fetcher.Paper) (*summarizer.Digest, error) {
+	m.calls++
+	summarizer.RecordUsage(ctx, m.backend, strings.TrimPrefix(m.backend, "test/"), m.input, m.output)
+	digest := sampleDigest()
+	digest.Backend = m.backend
+	return digest, nil
+}
+
 type mockPublisher struct {
 	published bool
+	digest    *summarizer.Digest
 	err       error
 }
 
 func (m *mockPublisher) Publish(ctx context.Context, digest *summarizer.Digest) error {
 	m.published = true
+	m.digest = digest
 	return m.err
 }
 
+type mockEnricher struct {
+	excerpt string
+}
+
+func (m *mockEnricher) Enrich(ctx context.Context, papers []fetcher.Paper) []fetcher.Paper {
+	for i := range papers {
+		papers[i].Excerpt = m.excerpt
+	}
+	return papers
+}
+
 func samplePapers() []fetcher.Paper {
 	return []fetcher.Paper{
 		{
@@ -143,4 +177,294 @@ func TestRunPublishFailureDoesNotFail(t *testing.T) {
 	if !successPub.published {
 		t.Error("Expected second publisher to be called even after first fails")
 	}
-}
\ No newline at end of file
+}
+
+func TestRunSkipsPublishedPapers(t *testing.T) {
+	store, err := state.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+
+	papers := []fetcher.Paper{
+		{ID: "2401.00001", Title: "First"},
+		{ID: "2401.00002", Title: "Second"},
+	}
+	digest := &summarizer.Digest{
+		Topic:     "test topic",
+		Date:      time.Now(),
+		Summaries: []summarizer.PaperSummary{{Paper: papers[0], Summary: "First summary."}},
+	}
+	summ := &mockSummarizer{digest: digest}
+
+	r := New("test topic", 10, &mockFetcher{papers: papers}, summ, []publisher.Publisher{&mockPublisher{}})
+	r.SetStore(store, 30*24*time.Hour)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("First run returned error: %v", err)
+	}
+	if len(summ.received) != 2 {
+		t.Fatalf("Expected 2 papers on first run, got %d", len(summ.received))
+	}
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Second run returned error: %v", err)
+	}
+	if len(summ.received) != 1 || summ.received[0].ID != "2401.00002" {
+		t.Errorf("Expected only the unpublished paper on second run, got %v", summ.received)
+	}
+}
+
+func TestRunDoesNotMarkPublishedWhenAllPublishersFail(t *testing.T) {
+	store, err := state.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+
+	papers := []fetcher.Paper{{ID: "2401.00001", Title: "First"}}
+	digest := &summarizer.Digest{
+		Topic:     "test topic",
+		Date:      time.Now(),
+		Summaries: []summarizer.PaperSummary{{Paper: papers[0]}},
+	}
+
+	r := New("test topic", 10, &mockFetcher{papers: papers}, &mockSummarizer{digest: digest},
+		[]publisher.Publisher{&mockPublisher{err: errors.New("publish failed")}})
+	r.SetStore(store, 0)
+
+	if err := r.Run(context.Background()); err == nil {
+		t.Fatal("Expected error when all publishers fail")
+	}
+
+	rec, ok, _ := store.Get("2401.00001")
+	if !ok {
+		t.Fatal("Expected fetched paper to be recorded")
+	}
+	if !rec.PublishedAt.IsZero() {
+		t.Error("Expected paper not to be marked published after failed publish")
+	}
+}
+
+func TestRunRecordsRuns(t *testing.T) {
+	l, err := runlog.Open(filepath.Join(t.TempDir(), "runs.jsonl"))
+	if err != nil {
+		t.Fatalf("runlog.Open returned error: %v", err)
+	}
+
+	r := New(
+		"test topic",
+		10,
+		&mockFetcher{papers: samplePapers()},
+		&mockSummarizer{digest: sampleDigest()},
+		[]publisher.Publisher{&mockPublisher{}, &mockPublisher{err: errors.New("publish failed")}},
+	)
+	r.SetRunLog(l)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	failing := New("test topic", 10, &mockFetcher{err: errors.New("fetch failed")}, &mockSummarizer{}, nil)
+	failing.SetRunLog(l)
+	failing.Run(context.Background())
+
+	runs, err := l.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 runs, got %d", len(runs))
+	}
+	if runs[0].Status != runlog.StatusFailed || runs[0].Error == "" {
+		t.Errorf("Expected failed run with error, got %+v", runs[0])
+	}
+	partial := runs[1]
+	if partial.Status != runlog.StatusPartial || partial.Fetched != 1 || partial.Summarized != 1 || partial.Published != 1 || partial.Failed != 1 {
+		t.Errorf("Unexpected partial run record: %+v", partial)
+	}
+}
+
+type mockSinceFetcher struct {
+	mockFetcher
+	since      []time.Time
+	sincePaper []fetcher.Paper
+	until      time.Time
+}
+
+func (m *mockSinceFetcher) FetchSince(ctx context.Context, topics []string, since time.Time) ([]fetcher.Paper, time.Time, error) {
+	m.since = append(m.since, since)
+	return m.sincePaper, m.until, nil
+}
+
+func TestRunIncrementalFetching(t *testing.T) {
+	store, err := state.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+
+	day1 := time.Date(2025, 1, 14, 18, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	f := &mockSinceFetcher{
+		mockFetcher: mockFetcher{papers: []fetcher.Paper{{ID: "a", Published: day1.Add(-time.Hour)}, {ID: "b", Published: day1}}},
+		sincePaper:  []fetcher.Paper{{ID: "c", Published: day2}},
+	}
+	pub := &mockPublisher{}
+
+	r := New("test topic", 10, f, &mockSummarizer{digest: sampleDigest()}, []publisher.Publisher{pub})
+	r.SetStore(store, 0)
+	r.SetIncremental(true)
+
+	// First run has no high-water mark yet and fetches the newest papers.
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("First run returned error: %v", err)
+	}
+	if len(f.since) != 0 {
+		t.Fatalf("Expected regular fetch on first run, got FetchSince calls %v", f.since)
+	}
+
+	// A failed publish must not advance the mark.
+	pub.err = errors.New("publish failed")
+	r.Run(context.Background())
+	pub.err = nil
+
+	for i := 0; i < 2; i++ {
+		if err := r.Run(context.Background()); err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	}
+	want := []time.Time{day1, day1, day2}
+	if len(f.since) != len(want) {
+		t.Fatalf("Expected %d FetchSince calls, got %v", len(want), f.since)
+	}
+	for i := range want {
+		if !f.since[i].Equal(want[i]) {
+			t.Errorf("FetchSince call %d: expected since %v, got %v", i+1, want[i], f.since[i])
+		}
+	}
+}
+
+func TestRunIncrementalFetchingStoppedEarly(t *testing.T) {
+	store, err := state.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+
+	mark := time.Date(2025, 1, 14, 18, 0, 0, 0, time.UTC)
+	until := mark.Add(6 * time.Hour)
+	f := &mockSinceFetcher{
+		sincePaper: []fetcher.Paper{{ID: "a", Published: until.Add(-time.Hour)}, {ID: "b", Published: mark.Add(24 * time.Hour)}},
+		until:      until,
+	}
+
+	r := New("test topic", 10, f, &mockSummarizer{digest: sampleDigest()}, []publisher.Publisher{&mockPublisher{}})
+	r.SetStore(store, 0)
+	r.SetIncremental(true)
+	if err := store.SetHighWater(r.highWaterKey(), mark); err != nil {
+		t.Fatalf("SetHighWater returned error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := r.Run(context.Background()); err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	}
+	// The mark stops where the fetcher stopped, not at the newest paper, so
+	// the papers it did not reach are fetched by the next run.
+	if len(f.since) != 2 || !f.since[0].Equal(mark) || !f.since[1].Equal(until) {
+		t.Errorf("Expected FetchSince from %v and then %v, got %v", mark, until, f.since)
+	}
+}
+
+func TestRunEnrichesPapersBeforeSummarizing(t *testing.T) {
+	sum := &mockSummarizer{digest: sampleDigest()}
+	r := New("test topic", 10, &mockFetcher{papers: samplePapers()}, sum, []publisher.Publisher{&mockPublisher{}})
+	r.SetEnricher(&mockEnricher{excerpt: "1 Introduction\nFull text."})
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(sum.received) != 1 || sum.received[0].Excerpt != "1 Introduction\nFull text." {
+		t.Errorf("Expected the summarizer to receive enriched papers, got %+v", sum.received)
+	}
+}
+
+func TestRunRecordsUsage(t *testing.T) {
+	l, err := runlog.Open(filepath.Join(t.TempDir(), "runs.jsonl"))
+	if err != nil {
+		t.Fatalf("runlog.Open returned error: %v", err)
+	}
+	pub := &mockPublisher{}
+	r := New("test topic", 10, &mockFetcher{papers: samplePapers()},
+		&meteredSummarizer{backend: "test/model-a", input: 1000, output: 200},
+		[]publisher.Publisher{pub})
+	r.SetRunLog(l)
+	r.SetPrices(map[string]summarizer.Price{"model-a": {Input: 3, Output: 15}}, true)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	const wantCost = (1000*3 + 200*15) / 1e6
+	runs, err := l.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(runs[0].Usage) != 1 || runs[0].Usage[0].InputTokens != 1000 || runs[0].Usage[0].OutputTokens != 200 {
+		t.Errorf("Expected the usage of test/model-a in the run record, got %+v", runs[0].Usage)
+	}
+	if runs[0].CostUSD != wantCost {
+		t.Errorf("Expected cost $%v, got $%v", wantCost, runs[0].CostUSD)
+	}
+	if len(pub.digest.Usage) != 1 || pub.digest.Usage[0].CostUSD != wantCost {
+		t.Errorf("Expected the usage on the published digest, got %+v", pub.digest.Usage)
+	}
+}
+
+func TestRunMonthlyBudget(t *testing.T) {
+	l, err := runlog.Open(filepath.Join(t.TempDir(), "runs.jsonl"))
+	if err != nil {
+		t.Fatalf("runlog.Open returned error: %v", err)
+	}
+	if err := l.Append(runlog.Run{StartedAt: time.Now(), Status: runlog.StatusSuccess, CostUSD: 5}); err != nil {
+		t.Fatalf("Append returned error: %v", err)
+	}
+
+	primary := &meteredSummarizer{backend: "test/expensive"}
+	cheap := &meteredSummarizer{backend: "test/cheap"}
+	pub := &mockPublisher{}
+	r := New("test topic", 10, &mockFetcher{papers: samplePapers()}, primary, []publisher.Publisher{pub})
+	r.SetRunLog(l)
+
+	// Within budget, the configured summarizer is used.
+	r.SetBudget(10, cheap)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if primary.calls != 1 || cheap.calls != 0 {
+		t.Errorf("Expected the primary summarizer within budget, got %d and %d calls", primary.calls, cheap.calls)
+	}
+
+	// Once the budget is spent, the cheaper one takes over.
+	r.SetBudget(5, cheap)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if primary.calls != 1 || cheap.calls != 1 || pub.digest.Backend != "test/cheap" {
+		t.Errorf("Expected the over-budget summarizer, got %d and %d calls", primary.calls, cheap.calls)
+	}
+
+	// Without one, the run is skipped.
+	pub.published = false
+	r.SetBudget(5, nil)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Expected a skipped run not to fail, got: %v", err)
+	}
+	if pub.published || primary.calls != 1 {
+		t.Error("Expected nothing to be summarized or published once the budget is spent")
+	}
+	runs, err := l.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if runs[0].Status != runlog.StatusSkipped {
+		t.Errorf("Expected a skipped run record, got %+v", runs[0])
+	}
+}
@@ -0,0 +1,146 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileData is the on-disk layout of a FileStore.
+type fileData struct {
+	Papers    map[string]Record    `json:"papers"`
+	HighWater map[string]time.Time `json:"high_water,omitempty"`
+}
+
+// FileStore is a Store backed by a single JSON file. The whole file is
+// rewritten atomically on every change, which is fine for the few thousand
+// records a daily digest accumulates.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+	data fileData
+}
+
+// NewFileStore opens the store at path, creating it on first write.
+func NewFileStore(path string) (*FileStore, error) {
+	fs := &FileStore{
+		path: path,
+		data: fileData{Papers: make(map[string]Record)},
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("state: failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(raw, &fs.data); err != nil {
+		return nil, fmt.Errorf("state: failed to parse %s: %w", path, err)
+	}
+	if fs.data.Papers == nil {
+		fs.data.Papers = make(map[string]Record)
+	}
+	return fs, nil
+}
+
+func (fs *FileStore) Mark(stage Stage, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for _, id := range ids {
+		rec := fs.data.Papers[id]
+		rec.ID = id
+		switch stage {
+		case StageFetched:
+			rec.FetchedAt = at
+		case StageSummarized:
+			rec.SummarizedAt = at
+		case StagePublished:
+			rec.PublishedAt = at
+		default:
+			return fmt.Errorf("state: unknown stage %q", stage)
+		}
+		fs.data.Papers[id] = rec
+	}
+	return fs.save()
+}
+
+func (fs *FileStore) Get(id string) (Record, bool, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	rec, ok := fs.data.Papers[id]
+	return rec, ok, nil
+}
+
+func (fs *FileStore) Prune(cutoff time.Time) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	pruned := 0
+	for id, rec := range fs.data.Papers {
+		if rec.LastSeen().Before(cutoff) {
+			delete(fs.data.Papers, id)
+			pruned++
+		}
+	}
+	if pruned == 0 {
+		return nil
+	}
+	return fs.save()
+}
+
+func (fs *FileStore) HighWater(key string) (time.Time, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.data.HighWater[key], nil
+}
+
+func (fs *FileStore) SetHighWater(key string, t time.Time) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.data.HighWater == nil {
+		fs.data.HighWater = make(map[string]time.Time)
+	}
+	fs.data.HighWater[key] = t
+	return fs.save()
+}
+
+func (fs *FileStore) Close() error {
+	return nil
+}
+
+// save writes the store to a temporary file and renames it into place so a
+// crash mid-write never leaves a truncated state file behind.
+func (fs *FileStore) save() error {
+	raw, err := json.MarshalIndent(fs.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("state: failed to encode: %w", err)
+	}
+
+	if dir := filepath.Dir(fs.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("state: failed to create %s: %w", dir, err)
+		}
+	}
+
+	tmp := fs.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return fmt.Errorf("state: failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, fs.path); err != nil {
+		return fmt.Errorf("state: failed to replace %s: %w", fs.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+func TestFileStoreMarkAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	fs, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+
+	now := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	if err := fs.Mark(StageFetched, []string{"2401.00001", "2401.00002"}, now); err != nil {
+		t.Fatalf("Mark fetched returned error: %v", err)
+	}
+	if err := fs.Mark(StagePublished, []string{"2401.00001"}, now); err != nil {
+		t.Fatalf("Mark published returned error: %v", err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Reopening store returned error: %v", err)
+	}
+
+	rec, ok, err := reloaded.Get("2401.00001")
+	if err != nil || !ok {
+		t.Fatalf("Expected record for 2401.00001, got ok=%v err=%v", ok, err)
+	}
+	if !rec.FetchedAt.Equal(now) || !rec.PublishedAt.Equal(now) {
+		t.Errorf("Unexpected record after reload: %+v", rec)
+	}
+
+	rec, ok, _ = reloaded.Get("2401.00002")
+	if !ok || !rec.PublishedAt.IsZero() {
+		t.Errorf("Expected unpublished record for 2401.00002, got %+v", rec)
+	}
+}
+
+func TestFileStorePrune(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+
+	old := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	fs.Mark(StagePublished, []string{"old"}, old)
+	fs.Mark(StagePublished, []string{"recent"}, recent)
+
+	if err := fs.Prune(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+
+	if _, ok, _ := fs.Get("old"); ok {
+		t.Error("Expected old record to be pruned")
+	}
+	if _, ok, _ := fs.Get("recent"); !ok {
+		t.Error("Expected recent record to be kept")
+	}
+}
+
+func TestFilterPublished(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+	now := time.Now()
+	fs.Mark(StageFetched, []string{"seen"}, now)
+	fs.Mark(StagePublished, []string{"published"}, now)
+
+	papers := []fetcher.Paper{
+		{ID: "published", Title: "Published"},
+		{ID: "seen", Title: "Seen"},
+		{ID: "new", Title: "New"},
+		{Title: "No ID"},
+	}
+
+	fresh, err := FilterPublished(fs, papers)
+	if err != nil {
+		t.Fatalf("FilterPublished returned error: %v", err)
+	}
+	if len(fresh) != 3 {
+		t.Fatalf("Expected 3 papers, got %d", len(fresh))
+	}
+	for _, p := range fresh {
+		if p.ID == "published" {
+			t.Error("Expected published paper to be filtered out")
+		}
+	}
+}
+
+func TestFileStoreHighWater(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	fs, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+
+	if hw, err := fs.HighWater("ml"); err != nil || !hw.IsZero() {
+		t.Fatalf("Expected zero high-water mark, got %v (err %v)", hw, err)
+	}
+
+	mark := time.Date(2025, 1, 15, 18, 0, 0, 0, time.UTC)
+	if err := fs.SetHighWater("ml", mark); err != nil {
+		t.Fatalf("SetHighWater returned error: %v", err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Reopening store returned error: %v", err)
+	}
+	if hw, _ := reloaded.HighWater("ml"); !hw.Equal(mark) {
+		t.Errorf("Expected high-water mark %v after reload, got %v", mark, hw)
+	}
+	if hw, _ := reloaded.HighWater("other"); !hw.IsZero() {
+		t.Errorf("Expected keys to be independent, got %v", hw)
+	}
+}
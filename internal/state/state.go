@@ -0,0 +1,82 @@
+package state
+
+import (
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+// Stage identifies how far a paper got through the pipeline.
+type Stage string
+
+const (
+	StageFetched    Stage = "fetched"
+	StageSummarized Stage = "summarized"
+	StagePublished  Stage = "published"
+)
+
+// Record holds what is known about a single paper across runs.
+type Record struct {
+	ID           string    `json:"id"`
+	FetchedAt    time.Time `json:"fetched_at,omitempty"`
+	SummarizedAt time.Time `json:"summarized_at,omitempty"`
+	PublishedAt  time.Time `json:"published_at,omitempty"`
+}
+
+// LastSeen returns the most recent time the paper reached any stage.
+func (r Record) LastSeen() time.Time {
+	last := r.FetchedAt
+	for _, t := range []time.Time{r.SummarizedAt, r.PublishedAt} {
+		if t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+// Store remembers which papers were already fetched, summarized and published.
+type Store interface {
+	// Mark records that the papers with the given IDs reached stage at time at.
+	Mark(stage Stage, ids []string, at time.Time) error
+	// Get returns the record for a paper ID and whether one exists.
+	Get(id string) (Record, bool, error)
+	// Prune forgets papers that were last seen before cutoff.
+	Prune(cutoff time.Time) error
+	// HighWater returns the publication time up to which papers for key have
+	// been considered, or the zero time if none was recorded.
+	HighWater(key string) (time.Time, error)
+	// SetHighWater records the high-water mark for key.
+	SetHighWater(key string, t time.Time) error
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// PaperIDs returns the IDs of the given papers, skipping papers without one.
+func PaperIDs(papers []fetcher.Paper) []string {
+	ids := make([]string, 0, len(papers))
+	for _, p := range papers {
+		if p.ID != "" {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids
+}
+
+// FilterPublished returns the papers that have not been published yet.
+// Papers without an ID are always kept.
+func FilterPublished(s Store, papers []fetcher.Paper) ([]fetcher.Paper, error) {
+	fresh := make([]fetcher.Paper, 0, len(papers))
+	for _, p := range papers {
+		if p.ID != "" {
+			rec, ok, err := s.Get(p.ID)
+			if err != nil {
+				return nil, err
+			}
+			if ok && !rec.PublishedAt.IsZero() {
+				continue
+			}
+		}
+		fresh = append(fresh, p)
+	}
+	return fresh, nil
+}
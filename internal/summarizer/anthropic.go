@@ -25,6 +25,8 @@ type AnthropicSummarizer struct {
 	language    string
 	client      *http.Client
 	retryConfig retry.Config
+	cache       *Cache   // Optional per-paper summary cache
+	prompts     *Prompts // Digest prompt templates; nil uses the built-in ones
 }
 
 func NewAnthropicSummarizer(apiKey, model string, maxTokens, topN int, topic, language string) *AnthropicSummarizer {
@@ -67,6 +69,20 @@ func NewAnthropicSummarizerMultiTopic(apiKey, model string, maxTokens, topN int,
 	}
 }
 
+// SetCache makes the summarizer reuse summaries of papers summarized before.
+func (s *AnthropicSummarizer) SetCache(cache *Cache) {
+	s.cache = cache
+}
+
+// SetPrompts replaces the built-in digest prompt templates.
+func (s *AnthropicSummarizer) SetPrompts(prompts *Prompts) {
+	s.prompts = prompts
+}
+
+func (s *AnthropicSummarizer) cacheScope() cacheScope {
+	return cacheScope{backend: s.Backend(), language: s.language, promptVersion: s.prompts.Version()}
+}
+
 // GetTopics returns the topics, prioritizing the new topics field over the legacy topic field.
 func (s *AnthropicSummarizer) GetTopics() []string {
 	if len(s.topics) > 0 {
@@ -86,24 +102,51 @@ func (s *AnthropicSummarizer) GetTopicsString() string {
 // Anthropic API request/response types
 
 type anthropicRequest struct {
-	Model     string             `json:"model"`
-	MaxTokens int                `json:"max_tokens"`
-	Messages  []anthropicMessage `json:"messages"`
+	Model      string               `json:"model"`
+	MaxTokens  int                  `json:"max_tokens"`
+	Messages   []anthropicMessage   `json:"messages"`
+	Tools      []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice *anthropicToolChoice `json:"tool_choice,omitempty"`
 }
 
 type anthropicMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string             `json:"role"`
+	Content []anthropicContent `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"` // auto | any | tool
+	Name string `json:"name,omitempty"`
 }
 
 type anthropicResponse struct {
 	Content []anthropicContent `json:"content"`
+	Usage   anthropicUsage     `json:"usage"`
 	Error   *anthropicError    `json:"error,omitempty"`
 }
 
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// anthropicContent is a content block: text, tool_use (model to client) or
+// tool_result (client to model).
 type anthropicContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`          // tool_use
+	Name      string          `json:"name,omitempty"`        // tool_use
+	Input     json.RawMessage `json:"input,omitempty"`       // tool_use
+	ToolUseID string          `json:"tool_use_id,omitempty"` // tool_result
+	Content   string          `json:"content,omitempty"`     // tool_result
+	IsError   bool            `json:"is_error,omitempty"`    // tool_result
 }
 
 type anthropicError struct {
@@ -111,195 +154,147 @@ type anthropicError struct {
 	Message string `json:"message"`
 }
 
-// digestJSON is the expected JSON structure from the LLM.
-type digestJSON struct {
-	Overview  string        `json:"overview"`
-	Summaries []summaryJSON `json:"summaries"`
-}
-
-type summaryJSON struct {
-	Index     int      `json:"index"`
-	Summary   string   `json:"summary"`
-	KeyPoints []string `json:"key_points"`
-}
+// maxRepairs is the number of times the model is asked to correct a
+// submit_digest call that fails validation.
+const maxRepairs = 1
 
 func (s *AnthropicSummarizer) Summarize(ctx context.Context, papers []fetcher.Paper) (*Digest, error) {
 	topics := s.GetTopics()
-	topicsString := s.GetTopicsString()
-	
+
 	if len(papers) == 0 {
-		noResultsText := fmt.Sprintf("No papers found for the given topic(s): %s.", topicsString)
-		if s.language == "ja" {
-			noResultsText = fmt.Sprintf("指定されたトピック「%s」に関する論文は見つかりませんでした。", topicsString)
-		}
-		return &Digest{
-			Topic:    s.topic, // For backward compatibility
-			Topics:   topics,
-			Date:     time.Now(),
-			Overview: noResultsText,
-		}, nil
+		digest := emptyDigest(s.topic, topics, s.language)
+		digest.Backend = s.Backend()
+		return digest, nil
 	}
 
-	prompt := s.buildPrompt(papers)
-
-	var body string
-	err := retry.WithBackoff(ctx, s.retryConfig, func(ctx context.Context) error {
-		var err error
-		body, err = s.callAPI(ctx, prompt)
-		return err
-	})
-	
+	if digest, ok := s.cache.digest(papers, s.cacheScope(), s.topN); ok {
+		digest.Topic, digest.Topics = s.topic, topics
+		digest.Backend = s.Backend()
+		digest.PromptVersion = s.prompts.Version()
+		digest.Language = s.language
+		return digest, nil
+	}
+	cached := s.cache.lookup(papers, s.cacheScope())
+	prompt, err := s.prompts.digestPrompt(papers, cached, topics, s.topN, s.language)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.parseResponse(body, papers, topics)
-}
-
-func (s *AnthropicSummarizer) buildPrompt(papers []fetcher.Paper) string {
-	var sb strings.Builder
-	topics := s.GetTopics()
-	topicsString := s.GetTopicsString()
-
-	if s.language == "ja" {
-		if len(topics) > 1 {
-			sb.WriteString(fmt.Sprintf("あなたは専門的な研究アナリストです。「%s」に関する%d件の最近の論文があります。\n\n", topicsString, len(papers)))
-		} else {
-			sb.WriteString(fmt.Sprintf("あなたは専門的な研究アナリストです。「%s」に関する%d件の最近の論文があります。\n\n", topicsString, len(papers)))
-		}
-	} else {
-		if len(topics) > 1 {
-			sb.WriteString(fmt.Sprintf("You are an expert research analyst. I have %d recent papers about \"%s\".\n\n", len(papers), topicsString))
-		} else {
-			sb.WriteString(fmt.Sprintf("You are an expert research analyst. I have %d recent papers about \"%s\".\n\n", len(papers), topicsString))
-		}
+	reqBody := anthropicRequest{
+		Model:     s.model,
+		MaxTokens: s.maxTokens,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: []anthropicContent{{Type: "text", Text: prompt}}},
+		},
+		Tools: []anthropicTool{{
+			Name:        digestToolName,
+			Description: digestToolDescription,
+			InputSchema: digestToolSchema,
+		}},
+		ToolChoice: &anthropicToolChoice{Type: "tool", Name: digestToolName},
 	}
 
-	for i, p := range papers {
-		sb.WriteString(fmt.Sprintf("--- Paper %d ---\n", i+1))
-		if s.language == "ja" {
-			sb.WriteString(fmt.Sprintf("タイトル: %s\n", p.Title))
-			sb.WriteString(fmt.Sprintf("著者: %s\n", strings.Join(p.Authors, ", ")))
-			sb.WriteString(fmt.Sprintf("カテゴリ: %s\n", p.Category))
-			sb.WriteString(fmt.Sprintf("要旨: %s\n\n", p.Abstract))
-		} else {
-			sb.WriteString(fmt.Sprintf("Title: %s\n", p.Title))
-			sb.WriteString(fmt.Sprintf("Authors: %s\n", strings.Join(p.Authors, ", ")))
-			sb.WriteString(fmt.Sprintf("Category: %s\n", p.Category))
-			sb.WriteString(fmt.Sprintf("Abstract: %s\n\n", p.Abstract))
+	for repairs := 0; ; repairs++ {
+		content, err := s.send(ctx, reqBody)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	if s.language == "ja" {
-		if len(topics) > 1 {
-			sb.WriteString(fmt.Sprintf(`これらの論文を分析し、以下を行ってください：
-1. 「%s」における重要性と関連性でランク付けする
-2. 最も重要な上位%d件の論文を選択する
-3. 選択した各論文について、明確な要約と3-5つのキーポイントを提供する
-4. 全体の簡潔な概要を書く（複数のトピック領域にわたる主要なトレンドと発見を含む）
-
-以下の正確な構造でJSONで応答してください：
-{
-  "overview": "複数のトピック領域における最も重要なトレンドと発見についての2-3文の概要",
-  "summaries": [
-    {
-      "index": 1,
-      "summary": "論文の2-3文の要約",
-      "key_points": ["ポイント1", "ポイント2", "ポイント3"]
-    }
-  ]
-}
-
-"index"フィールドは上記リストの1ベースの論文番号である必要があります。
-有効なJSONのみで応答し、マークダウンフェンスや追加のテキストは含めないでください。`, topicsString, s.topN))
-		} else {
-			sb.WriteString(fmt.Sprintf(`これらの論文を分析し、以下を行ってください：
-1. 「%s」における重要性と関連性でランク付けする
-2. 最も重要な上位%d件の論文を選択する
-3. 選択した各論文について、明確な要約と3-5つのキーポイントを提供する
-4. 全体の簡潔な概要を書く
-
-以下の正確な構造でJSONで応答してください：
-{
-  "overview": "最も重要なトレンドと発見についての2-3文の概要",
-  "summaries": [
-    {
-      "index": 1,
-      "summary": "論文の2-3文の要約",
-      "key_points": ["ポイント1", "ポイント2", "ポイント3"]
-    }
-  ]
-}
 
-"index"フィールドは上記リストの1ベースの論文番号である必要があります。
-有効なJSONのみで応答し、マークダウンフェンスや追加のテキストは含めないでください。`, topicsString, s.topN))
+		use := findToolUse(content)
+		if use == nil {
+			// The model answered in text despite the forced tool choice,
+			// which some proxies do; fall back to parsing the text.
+			var text string
+			for _, c := range content {
+				if c.Type == "text" {
+					text = c.Text
+					break
+				}
+			}
+			digest, err := s.parseResponse(text, papers, topics)
+			if err != nil {
+				return nil, err
+			}
+			digest.Backend = s.Backend()
+			digest.PromptVersion = s.prompts.Version()
+			digest.Language = s.language
+			s.cache.apply(digest, papers, s.cacheScope(), s.topN)
+			return digest, nil
 		}
-	} else {
-		if len(topics) > 1 {
-			sb.WriteString(fmt.Sprintf(`Please analyze these papers and:
-1. Rank them by importance and relevance to "%s"
-2. Select the top %d most important papers
-3. For each selected paper, provide a clear summary and 3-5 key points
-4. Write a brief overall digest overview that captures key trends and findings across multiple topic areas
-
-Respond in JSON with this exact structure:
-{
-  "overview": "A 2-3 sentence overview of the most important trends and findings across multiple topics",
-  "summaries": [
-    {
-      "index": 1,
-      "summary": "2-3 sentence summary of the paper",
-      "key_points": ["point 1", "point 2", "point 3"]
-    }
-  ]
-}
-
-The "index" field should be the 1-based paper number from the list above.
-Respond ONLY with valid JSON, no markdown fences or additional text.`, topicsString, s.topN))
-		} else {
-			sb.WriteString(fmt.Sprintf(`Please analyze these papers and:
-1. Rank them by importance and relevance to "%s"
-2. Select the top %d most important papers
-3. For each selected paper, provide a clear summary and 3-5 key points
-4. Write a brief overall digest overview
-
-Respond in JSON with this exact structure:
-{
-  "overview": "A 2-3 sentence overview of the most important trends and findings",
-  "summaries": [
-    {
-      "index": 1,
-      "summary": "2-3 sentence summary of the paper",
-      "key_points": ["point 1", "point 2", "point 3"]
-    }
-  ]
-}
 
-The "index" field should be the 1-based paper number from the list above.
-Respond ONLY with valid JSON, no markdown fences or additional text.`, topicsString, s.topN))
+		dj, problems := validateDigestInput(use.Input, len(papers), cached)
+		if len(problems) == 0 {
+			digest := newDigest(dj, papers, s.topic, topics)
+			digest.Backend = s.Backend()
+			digest.PromptVersion = s.prompts.Version()
+			digest.Language = s.language
+			s.cache.apply(digest, papers, s.cacheScope(), s.topN)
+			return digest, nil
+		}
+		if repairs == maxRepairs {
+			return nil, fmt.Errorf("anthropic: invalid %s input: %s", digestToolName, strings.Join(problems, "; "))
 		}
-	}
 
-	return sb.String()
+		// Hand the problems back as a failed tool result and ask again.
+		reqBody.Messages = append(reqBody.Messages,
+			anthropicMessage{Role: "assistant", Content: content},
+			anthropicMessage{Role: "user", Content: []anthropicContent{{
+				Type:      "tool_result",
+				ToolUseID: use.ID,
+				IsError:   true,
+				Content:   fmt.Sprintf("The input is invalid: %s. Call %s again with corrected input.", strings.Join(problems, "; "), digestToolName),
+			}}},
+		)
+	}
 }
 
-func (s *AnthropicSummarizer) callAPI(ctx context.Context, prompt string) (string, error) {
-	reqBody := anthropicRequest{
+// Complete sends prompt as a single user message, without tools, and returns
+// the text of the answer, bounded by maxTokens. It implements Completer.
+func (s *AnthropicSummarizer) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	content, err := s.send(ctx, anthropicRequest{
 		Model:     s.model,
-		MaxTokens: s.maxTokens,
+		MaxTokens: maxTokens,
 		Messages: []anthropicMessage{
-			{Role: "user", Content: prompt},
+			{Role: "user", Content: []anthropicContent{{Type: "text", Text: prompt}}},
 		},
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, c := range content {
+		if c.Type == "text" {
+			return c.Text, nil
+		}
 	}
+	return "", fmt.Errorf("anthropic: no text in response")
+}
 
+// Backend names the API and model, e.g. "anthropic/claude-sonnet-4-20250514".
+func (s *AnthropicSummarizer) Backend() string {
+	return "anthropic/" + s.model
+}
+
+// send calls the API with retries.
+func (s *AnthropicSummarizer) send(ctx context.Context, reqBody anthropicRequest) ([]anthropicContent, error) {
+	var content []anthropicContent
+	err := retry.WithBackoff(ctx, s.retryConfig, func(ctx context.Context) error {
+		var err error
+		content, err = s.callAPI(ctx, reqBody)
+		return err
+	})
+	return content, err
+}
+
+// callAPI sends a request and returns the content blocks of the reply.
+func (s *AnthropicSummarizer) callAPI(ctx context.Context, reqBody anthropicRequest) ([]anthropicContent, error) {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("anthropic: failed to marshal request: %w", err)
+		return nil, fmt.Errorf("anthropic: failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.anthropic.com/v1/messages", bytes.NewReader(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("anthropic: failed to create request: %w", err)
+		return nil, fmt.Errorf("anthropic: failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-api-key", s.apiKey)
@@ -307,71 +302,55 @@ func (s *AnthropicSummarizer) callAPI(ctx context.Context, prompt string) (strin
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("anthropic: request failed: %w", err)
+		return nil, fmt.Errorf("anthropic: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("anthropic: failed to read response: %w", err)
+		return nil, fmt.Errorf("anthropic: failed to read response: %w", err)
 	}
 
 	// Check for HTTP errors first
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		// Non-retryable client errors should be handled differently
 		if !retry.HTTPStatusRetryable(resp.StatusCode) {
-			return "", fmt.Errorf("anthropic: API error with status %d: %s", resp.StatusCode, string(respBody))
+			return nil, fmt.Errorf("anthropic: API error with status %d: %s", resp.StatusCode, string(respBody))
 		}
-		return "", fmt.Errorf("anthropic: unexpected status %d", resp.StatusCode)
+		return nil, fmt.Errorf("anthropic: unexpected status %d", resp.StatusCode)
 	}
 
 	var apiResp anthropicResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return "", fmt.Errorf("anthropic: failed to parse response: %w", err)
+		return nil, fmt.Errorf("anthropic: failed to parse response: %w", err)
 	}
 
 	if apiResp.Error != nil {
-		return "", fmt.Errorf("anthropic: API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
+		return nil, fmt.Errorf("anthropic: API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
 	}
+	RecordUsage(ctx, s.Backend(), s.model, apiResp.Usage.InputTokens, apiResp.Usage.OutputTokens)
 
 	if len(apiResp.Content) == 0 {
-		return "", fmt.Errorf("anthropic: empty response")
+		return nil, fmt.Errorf("anthropic: empty response")
 	}
 
-	return apiResp.Content[0].Text, nil
+	return apiResp.Content, nil
 }
 
-func (s *AnthropicSummarizer) parseResponse(body string, papers []fetcher.Paper, topics []string) (*Digest, error) {
-	// Strip markdown fences if present
-	body = strings.TrimSpace(body)
-	body = strings.TrimPrefix(body, "```json")
-	body = strings.TrimPrefix(body, "```")
-	body = strings.TrimSuffix(body, "```")
-	body = strings.TrimSpace(body)
-
-	var dj digestJSON
-	if err := json.Unmarshal([]byte(body), &dj); err != nil {
-		return nil, fmt.Errorf("anthropic: failed to parse LLM JSON: %w\nraw response: %s", err, body)
-	}
-
-	digest := &Digest{
-		Topic:    s.topic, // For backward compatibility
-		Topics:   topics,
-		Date:     time.Now(),
-		Overview: dj.Overview,
-	}
-
-	for _, sj := range dj.Summaries {
-		idx := sj.Index - 1 // Convert from 1-based to 0-based
-		if idx < 0 || idx >= len(papers) {
-			continue
+// findToolUse returns the submit_digest call among the content blocks, or nil.
+func findToolUse(content []anthropicContent) *anthropicContent {
+	for i := range content {
+		if content[i].Type == "tool_use" && content[i].Name == digestToolName {
+			return &content[i]
 		}
-		digest.Summaries = append(digest.Summaries, PaperSummary{
-			Paper:     papers[idx],
-			Summary:   sj.Summary,
-			KeyPoints: sj.KeyPoints,
-		})
 	}
+	return nil
+}
 
+func (s *AnthropicSummarizer) parseResponse(body string, papers []fetcher.Paper, topics []string) (*Digest, error) {
+	digest, err := parseDigest(body, papers, s.topic, topics)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: %w", err)
+	}
 	return digest, nil
-}
\ No newline at end of file
+}
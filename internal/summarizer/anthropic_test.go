@@ -122,7 +122,10 @@ func TestBuildPromptFormat(t *testing.T) {
 	s := &AnthropicSummarizer{topic: "machine learning", topN: 3, language: "en"}
 	papers := samplePapers()
 
-	prompt := s.buildPrompt(papers)
+	prompt, err := s.prompts.digestPrompt(papers, nil, s.GetTopics(), s.topN, s.language)
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
 
 	if !strings.Contains(prompt, "2 recent papers") {
 		t.Error("Expected prompt to mention number of papers")
@@ -151,7 +154,10 @@ func TestBuildPromptJapanese(t *testing.T) {
 	s := &AnthropicSummarizer{topic: "machine learning", topN: 3, language: "ja"}
 	papers := samplePapers()
 
-	prompt := s.buildPrompt(papers)
+	prompt, err := s.prompts.digestPrompt(papers, nil, s.GetTopics(), s.topN, s.language)
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
 
 	if !strings.Contains(prompt, "専門的な研究アナリスト") {
 		t.Error("Expected Japanese prompt to contain expert analyst text")
@@ -269,6 +275,9 @@ func TestSummarizeWithMockAPI(t *testing.T) {
 	if digest.Summaries[0].Paper.Title != "Paper One" {
 		t.Errorf("Expected paper title 'Paper One', got %q", digest.Summaries[0].Paper.Title)
 	}
+	if digest.Backend != "anthropic/test-model" {
+		t.Errorf("Expected backend 'anthropic/test-model', got %q", digest.Backend)
+	}
 }
 
 func TestSummarizeAPIError(t *testing.T) {
@@ -304,6 +313,207 @@ func TestSummarizeAPIError(t *testing.T) {
 	}
 }
 
+// toolUseResponse is an Anthropic response calling submit_digest with input.
+func toolUseResponse(input string) string {
+	return `{"content": [{"type": "tool_use", "id": "toolu_1", "name": "submit_digest", "input": ` + input + `}], "stop_reason": "tool_use"}`
+}
+
+func TestSummarizeWithToolUse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].Name != "submit_digest" || len(req.Tools[0].InputSchema) == 0 {
+			t.Errorf("Expected the submit_digest tool, got %+v", req.Tools)
+		}
+		if req.ToolChoice == nil || req.ToolChoice.Type != "tool" || req.ToolChoice.Name != "submit_digest" {
+			t.Errorf("Expected submit_digest to be forced, got %+v", req.ToolChoice)
+		}
+		w.Write([]byte(toolUseResponse(`{"overview": "Tool overview.", "summaries": [{"index": 2, "summary": "Two.", "key_points": ["p"]}]}`)))
+	}))
+	defer ts.Close()
+
+	s := &AnthropicSummarizer{
+		model:    "test-model",
+		topN:     5,
+		topic:    "AI",
+		language: "en",
+		client:   &http.Client{Transport: &rewriteTransport{testURL: ts.URL}},
+	}
+
+	digest, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if digest.Overview != "Tool overview." {
+		t.Errorf("Expected overview 'Tool overview.', got %q", digest.Overview)
+	}
+	if len(digest.Summaries) != 1 || digest.Summaries[0].Paper.Title != "Paper Two" {
+		t.Errorf("Expected a summary of Paper Two, got %+v", digest.Summaries)
+	}
+}
+
+func TestSummarizeRepairsInvalidToolInput(t *testing.T) {
+	var requests []anthropicRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			w.Write([]byte(toolUseResponse(`{"overview": "o", "summaries": [{"index": 7, "summary": "s", "key_points": []}]}`)))
+			return
+		}
+		w.Write([]byte(toolUseResponse(`{"overview": "o", "summaries": [{"index": 1, "summary": "s", "key_points": []}]}`)))
+	}))
+	defer ts.Close()
+
+	s := &AnthropicSummarizer{
+		model:    "test-model",
+		topN:     5,
+		topic:    "AI",
+		language: "en",
+		client:   &http.Client{Transport: &rewriteTransport{testURL: ts.URL}},
+	}
+
+	digest, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if len(digest.Summaries) != 1 || digest.Summaries[0].Paper.Title != "Paper One" {
+		t.Errorf("Expected the repaired summary of Paper One, got %+v", digest.Summaries)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected one repair request, got %d requests", len(requests))
+	}
+	msgs := requests[1].Messages
+	if len(msgs) != 3 || msgs[1].Role != "assistant" || msgs[1].Content[0].ID != "toolu_1" {
+		t.Fatalf("Expected the prompt, the tool call and its result, got %+v", msgs)
+	}
+	result := msgs[2].Content[0]
+	if result.Type != "tool_result" || result.ToolUseID != "toolu_1" || !result.IsError {
+		t.Errorf("Expected an error tool result for toolu_1, got %+v", result)
+	}
+	if !strings.Contains(result.Content, "summaries[0].index 7 is out of range") {
+		t.Errorf("Expected the validation problem in the tool result, got %q", result.Content)
+	}
+}
+
+func TestSummarizeFailsAfterRepair(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(toolUseResponse(`{"summaries": []}`)))
+	}))
+	defer ts.Close()
+
+	s := &AnthropicSummarizer{
+		model:    "test-model",
+		topN:     5,
+		topic:    "AI",
+		language: "en",
+		client:   &http.Client{Transport: &rewriteTransport{testURL: ts.URL}},
+	}
+
+	_, err := s.Summarize(context.Background(), samplePapers())
+	if err == nil || !strings.Contains(err.Error(), "invalid submit_digest input: overview is required") {
+		t.Errorf("Expected a validation error, got: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected exactly one repair round, got %d calls", calls)
+	}
+}
+
+func TestValidateDigestInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		cached    map[int]cachedSummary
+		wantProbs []string
+	}{
+		{
+			name:  "valid",
+			input: `{"overview": "o", "summaries": [{"index": 1, "summary": "s", "key_points": ["k"]}, {"index": 2, "summary": "s", "key_points": []}]}`,
+		},
+		{
+			name:      "missing fields",
+			input:     `{"summaries": [{"index": 1}]}`,
+			wantProbs: []string{"overview is required", "summaries[0].summary is required", "summaries[0].key_points is required"},
+		},
+		{
+			name:   "cached paper without summary",
+			input:  `{"overview": "o", "summaries": [{"index": 2}]}`,
+			cached: map[int]cachedSummary{1: {Summary: "s"}},
+		},
+		{
+			name:      "uncached paper without summary",
+			input:     `{"overview": "o", "summaries": [{"index": 1}]}`,
+			cached:    map[int]cachedSummary{1: {Summary: "s"}},
+			wantProbs: []string{"summaries[0].summary is required", "summaries[0].key_points is required"},
+		},
+		{
+			name:      "duplicate index",
+			input:     `{"overview": "o", "summaries": [{"index": 1, "summary": "s", "key_points": []}, {"index": 1, "summary": "s", "key_points": []}]}`,
+			wantProbs: []string{"summaries[1].index 1 is a duplicate"},
+		},
+		{
+			name:      "wrong type",
+			input:     `{"overview": "o", "summaries": [{"index": "1", "summary": "s", "key_points": []}]}`,
+			wantProbs: []string{"input does not match the schema"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, problems := validateDigestInput(json.RawMessage(tt.input), 2, tt.cached)
+			if len(problems) != len(tt.wantProbs) {
+				t.Fatalf("Expected problems %q, got %q", tt.wantProbs, problems)
+			}
+			for i, want := range tt.wantProbs {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("Expected problem %q, got %q", want, problems[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeReusesCachedDigest(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(toolUseResponse(`{"overview": "Tool overview.", "summaries": [{"index": 2, "summary": "Two.", "key_points": ["p"]}]}`)))
+	}))
+	defer ts.Close()
+
+	cache, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCache returned error: %v", err)
+	}
+	s := &AnthropicSummarizer{
+		model:    "test-model",
+		topN:     5,
+		topic:    "AI",
+		language: "en",
+		client:   &http.Client{Transport: &rewriteTransport{testURL: ts.URL}},
+	}
+	s.SetCache(cache)
+
+	for i := 0; i < 2; i++ {
+		digest, err := s.Summarize(context.Background(), samplePapers())
+		if err != nil {
+			t.Fatalf("Summarize %d returned error: %v", i+1, err)
+		}
+		if digest.Overview != "Tool overview." || len(digest.Summaries) != 1 || digest.Summaries[0].Summary != "Two." {
+			t.Errorf("Summarize %d: unexpected digest %+v", i+1, digest)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the rerun over cached papers to skip the model, got %d requests", requests)
+	}
+}
+
 // rewriteTransport redirects all requests to the test server URL.
 type rewriteTransport struct {
 	base    http.RoundTripper
@@ -328,4 +538,66 @@ func mustMarshal(t *testing.T, v any) string {
 		t.Fatalf("Failed to marshal: %v", err)
 	}
 	return string(b)
-}
\ No newline at end of file
+}
+func TestBuildPromptIncludesPublicationNotes(t *testing.T) {
+	s := &AnthropicSummarizer{topic: "vision", topN: 3, language: "en"}
+	papers := samplePapers()
+	papers[0].Comment = "Accepted at CVPR 2025"
+	papers[0].JournalRef = "Proc. CVPR 2025"
+
+	prompt, err := s.prompts.digestPrompt(papers, nil, s.GetTopics(), s.topN, s.language)
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+
+	if !strings.Contains(prompt, "Comments: Accepted at CVPR 2025\n") {
+		t.Error("Expected prompt to contain the author comment")
+	}
+	if !strings.Contains(prompt, "Journal reference: Proc. CVPR 2025\n") {
+		t.Error("Expected prompt to contain the journal reference")
+	}
+	if strings.Count(prompt, "Comments:") != 1 {
+		t.Error("Expected no comment line for papers without a comment")
+	}
+}
+
+func TestBuildPromptIncludesMatchedTopics(t *testing.T) {
+	papers := samplePapers()
+	papers[0].Topics = []string{"vision", "robotics"}
+
+	multi := &AnthropicSummarizer{topics: []string{"vision", "robotics"}, topN: 3, language: "en"}
+	prompt, err := multi.prompts.digestPrompt(papers, nil, multi.GetTopics(), multi.topN, multi.language)
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+	if !strings.Contains(prompt, "Matched topics: vision, robotics\n") {
+		t.Error("Expected multi-topic prompt to contain the matched topics")
+	}
+
+	single := &AnthropicSummarizer{topic: "vision", topN: 3, language: "en"}
+	prompt, err = single.prompts.digestPrompt(papers, nil, single.GetTopics(), single.topN, single.language)
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+	if strings.Contains(prompt, "Matched topics:") {
+		t.Error("Expected single-topic prompt to omit matched topics")
+	}
+}
+
+func TestBuildPromptIncludesFullTextExcerpt(t *testing.T) {
+	s := &AnthropicSummarizer{topic: "vision", topN: 3, language: "en"}
+	papers := samplePapers()
+	papers[0].Excerpt = "1 Introduction\nWe study depth estimation."
+
+	prompt, err := s.prompts.digestPrompt(papers, nil, s.GetTopics(), s.topN, s.language)
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+
+	if !strings.Contains(prompt, "Full text excerpt:\n1 Introduction\nWe study depth estimation.\n") {
+		t.Error("Expected prompt to contain the full-text excerpt")
+	}
+	if strings.Count(prompt, "Full text excerpt:") != 1 {
+		t.Error("Expected no excerpt section for papers without full text")
+	}
+}
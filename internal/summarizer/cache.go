@@ -0,0 +1,232 @@
+package summarizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+// Cache stores paper summaries on disk so that a paper summarized in an
+// earlier run is not sent to the model again. Entries are content-addressed
+// by the paper ID, a hash of its abstract, the full-text setting, the backend
+// (which includes the model), the language and the prompt version, so any
+// change to one of these produces a fresh summary. The cache also keeps the
+// overview and selection of each digest, so that a rerun over the same papers
+// needs no model call at all. A nil *Cache disables caching.
+type Cache struct {
+	dir      string
+	fullText int // Excerpt length summaries are written from; 0 without full text
+}
+
+// cacheScope is what a summary depends on besides the paper.
+type cacheScope struct {
+	backend       string // Includes the model
+	language      string
+	promptVersion string
+}
+
+// cachedSummary is a stored summary of a paper.
+type cachedSummary struct {
+	PaperID   string   `json:"paper_id"` // For inspection only
+	Summary   string   `json:"summary"`
+	KeyPoints []string `json:"key_points"`
+	Score     *int     `json:"score,omitempty"` // Relevance score from the map phase
+}
+
+// cachedDigest is the stored result of summarizing a set of papers. The
+// summaries of the selected papers are cached separately.
+type cachedDigest struct {
+	Overview string   `json:"overview"`
+	Selected []string `json:"selected"` // Paper keys in digest order
+}
+
+// NewCache returns a cache storing one file per summary in dir.
+func NewCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("summarizer: failed to create cache dir %s: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// SetFullText records the length of the full-text excerpts summaries are
+// written from, or zero when full text is off. It is part of every key rather
+// than the excerpt itself, which depends on the download budget of the run.
+func (c *Cache) SetFullText(maxChars int) {
+	if c != nil {
+		c.fullText = maxChars
+	}
+}
+
+// key derives the content address of a paper's summary.
+func (c *Cache) key(p fetcher.Paper, scope cacheScope) string {
+	content := sha256.Sum256([]byte(p.Abstract))
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		paperKey(p),
+		hex.EncodeToString(content[:]),
+		fmt.Sprintf("fulltext=%d", c.fullText),
+		scope.backend,
+		scope.language,
+		scope.promptVersion,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// digestKey derives the content address of the digest of papers, which
+// depends on every paper, in order, and on how many are selected.
+func (c *Cache) digestKey(papers []fetcher.Paper, scope cacheScope, topN int) string {
+	parts := []string{"digest", fmt.Sprintf("top=%d", topN)}
+	for _, p := range papers {
+		parts = append(parts, c.key(p, scope))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// paperKey identifies a paper: its ID, or its URL or title for sources
+// without IDs.
+func paperKey(p fetcher.Paper) string {
+	switch {
+	case p.ID != "":
+		return p.ID
+	case p.URL != "":
+		return p.URL
+	}
+	return p.Title
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// get returns the cached summary of a paper.
+func (c *Cache) get(p fetcher.Paper, scope cacheScope) (cachedSummary, bool) {
+	data, err := os.ReadFile(c.path(c.key(p, scope)))
+	if err != nil {
+		return cachedSummary{}, false
+	}
+	var cs cachedSummary
+	if err := json.Unmarshal(data, &cs); err != nil || cs.Summary == "" {
+		return cachedSummary{}, false
+	}
+	return cs, true
+}
+
+// lookup returns the cached summaries of papers by index.
+func (c *Cache) lookup(papers []fetcher.Paper, scope cacheScope) map[int]cachedSummary {
+	if c == nil {
+		return nil
+	}
+	cached := make(map[int]cachedSummary)
+	for i, p := range papers {
+		if cs, ok := c.get(p, scope); ok {
+			cached[i] = cs
+		}
+	}
+	if len(cached) > 0 {
+		log.Printf("Reusing cached summaries for %d of %d papers", len(cached), len(papers))
+	}
+	return cached
+}
+
+// store saves a summary. Failures are logged, since the cache only saves
+// work.
+func (c *Cache) store(p fetcher.Paper, scope cacheScope, cs cachedSummary) {
+	if c == nil || cs.Summary == "" {
+		return
+	}
+	cs.PaperID = paperKey(p)
+	data, err := json.MarshalIndent(cs, "", "  ")
+	if err == nil {
+		err = os.WriteFile(c.path(c.key(p, scope)), data, 0o644)
+	}
+	if err != nil {
+		log.Printf("WARNING: failed to cache summary of %s: %v", cs.PaperID, err)
+	}
+}
+
+// apply completes a digest summarized with cached summaries in the prompt:
+// selected papers the model did not summarize again get their cached
+// summary, and fresh summaries are stored along with the digest of papers.
+func (c *Cache) apply(digest *Digest, papers []fetcher.Paper, scope cacheScope, topN int) {
+	if c == nil {
+		return
+	}
+	for i, ps := range digest.Summaries {
+		if ps.Summary != "" {
+			c.store(ps.Paper, scope, cachedSummary{Summary: ps.Summary, KeyPoints: ps.KeyPoints})
+			continue
+		}
+		if cs, ok := c.get(ps.Paper, scope); ok {
+			digest.Summaries[i].Summary = cs.Summary
+			digest.Summaries[i].KeyPoints = cs.KeyPoints
+		}
+	}
+	c.storeDigest(digest, papers, scope, topN)
+}
+
+// storeDigest saves the overview and selection of the digest of papers once
+// all its summaries are cached. Failures are logged like those of store.
+func (c *Cache) storeDigest(digest *Digest, papers []fetcher.Paper, scope cacheScope, topN int) {
+	if c == nil || len(digest.Summaries) == 0 {
+		return
+	}
+	cd := cachedDigest{Overview: digest.Overview, Selected: make([]string, len(digest.Summaries))}
+	for i, ps := range digest.Summaries {
+		if ps.Summary == "" {
+			return
+		}
+		cd.Selected[i] = paperKey(ps.Paper)
+	}
+	data, err := json.MarshalIndent(cd, "", "  ")
+	if err == nil {
+		err = os.WriteFile(c.path(c.digestKey(papers, scope, topN)), data, 0o644)
+	}
+	if err != nil {
+		log.Printf("WARNING: failed to cache digest: %v", err)
+	}
+}
+
+// digest rebuilds the digest of papers stored by an earlier run when the
+// summaries of all its selected papers are still cached, so that summarizing
+// the same papers again needs no model call. Only Overview, Summaries and
+// Date are set.
+func (c *Cache) digest(papers []fetcher.Paper, scope cacheScope, topN int) (*Digest, bool) {
+	if c == nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(c.digestKey(papers, scope, topN)))
+	if err != nil {
+		return nil, false
+	}
+	var cd cachedDigest
+	if err := json.Unmarshal(data, &cd); err != nil || len(cd.Selected) == 0 {
+		return nil, false
+	}
+
+	byKey := make(map[string]fetcher.Paper, len(papers))
+	for _, p := range papers {
+		byKey[paperKey(p)] = p
+	}
+	digest := &Digest{Date: time.Now(), Overview: cd.Overview}
+	for _, key := range cd.Selected {
+		p, ok := byKey[key]
+		if !ok {
+			return nil, false
+		}
+		cs, ok := c.get(p, scope)
+		if !ok {
+			return nil, false
+		}
+		digest.Summaries = append(digest.Summaries, PaperSummary{Paper: p, Summary: cs.Summary, KeyPoints: cs.KeyPoints})
+	}
+	log.Printf("Reusing the cached digest of %d papers", len(papers))
+	return digest, true
+}
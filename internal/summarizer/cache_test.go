@@ -0,0 +1,139 @@
+package summarizer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+var testScope = cacheScope{backend: "test/model", language: "en", promptVersion: "v1"}
+
+func TestCacheKey(t *testing.T) {
+	c := &Cache{}
+	p := fetcher.Paper{ID: "2401.00001", Title: "Paper", Abstract: "Abstract."}
+	key := c.key(p, testScope)
+
+	if c.key(p, testScope) != key {
+		t.Error("Expected the same key for the same paper and scope")
+	}
+	withExcerpt := p
+	withExcerpt.Excerpt = "Introduction."
+	if c.key(withExcerpt, testScope) != key {
+		t.Error("Expected the excerpt, which depends on the download budget, not to change the key")
+	}
+
+	revised := p
+	revised.Abstract = "Revised abstract."
+	otherModel, otherLanguage, otherPrompts := testScope, testScope, testScope
+	otherModel.backend = "test/other-model"
+	otherLanguage.language = "ja"
+	otherPrompts.promptVersion = "v2"
+	withFullText := &Cache{}
+	withFullText.SetFullText(4000)
+	for name, other := range map[string]string{
+		"abstract":       c.key(revised, testScope),
+		"full text":      withFullText.key(p, testScope),
+		"model":          c.key(p, otherModel),
+		"language":       c.key(p, otherLanguage),
+		"prompt version": c.key(p, otherPrompts),
+	} {
+		if other == key {
+			t.Errorf("Expected a different key when the %s changes", name)
+		}
+	}
+}
+
+func TestCacheApply(t *testing.T) {
+	c, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCache returned error: %v", err)
+	}
+	papers := samplePapers()
+
+	// A fresh summary is stored.
+	c.apply(&Digest{Summaries: []PaperSummary{
+		{Paper: papers[0], Summary: "Summary one.", KeyPoints: []string{"k1"}},
+	}}, papers[:1], testScope, 5)
+
+	cached := c.lookup(papers, testScope)
+	if len(cached) != 1 || cached[0].Summary != "Summary one." || cached[0].PaperID != papers[0].URL {
+		t.Fatalf("Expected the stored summary of paper one, got %+v", cached)
+	}
+	otherLanguage := testScope
+	otherLanguage.language = "ja"
+	if len(c.lookup(papers, otherLanguage)) != 0 {
+		t.Error("Expected no cached summaries in another language")
+	}
+
+	// A selected paper without a summary gets the cached one.
+	digest := &Digest{Summaries: []PaperSummary{{Paper: papers[0]}, {Paper: papers[1]}}}
+	c.apply(digest, papers, testScope, 5)
+	if digest.Summaries[0].Summary != "Summary one." || len(digest.Summaries[0].KeyPoints) != 1 {
+		t.Errorf("Expected the cached summary to be filled in, got %+v", digest.Summaries[0])
+	}
+	if digest.Summaries[1].Summary != "" {
+		t.Errorf("Expected no summary for an uncached paper, got %q", digest.Summaries[1].Summary)
+	}
+}
+
+func TestCacheDigest(t *testing.T) {
+	c, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCache returned error: %v", err)
+	}
+	papers := samplePapers()
+
+	// A digest with a selected paper lacking a summary is not stored.
+	c.apply(&Digest{Overview: "Partial.", Summaries: []PaperSummary{{Paper: papers[1]}}}, papers, testScope, 5)
+	if _, ok := c.digest(papers, testScope, 5); ok {
+		t.Fatal("Expected no digest while a selected summary is missing")
+	}
+
+	c.apply(&Digest{Overview: "Overview.", Summaries: []PaperSummary{
+		{Paper: papers[1], Summary: "Summary two.", KeyPoints: []string{"k2"}},
+		{Paper: papers[0], Summary: "Summary one."},
+	}}, papers, testScope, 5)
+
+	digest, ok := c.digest(papers, testScope, 5)
+	if !ok {
+		t.Fatal("Expected the digest of the same papers to be cached")
+	}
+	if digest.Overview != "Overview." || len(digest.Summaries) != 2 || digest.Summaries[0].Paper.Title != "Paper Two" || digest.Summaries[0].KeyPoints[0] != "k2" {
+		t.Errorf("Expected the stored overview and selection, got %+v", digest)
+	}
+	for name, lookup := range map[string]func() bool{
+		"other papers": func() bool { _, ok := c.digest(papers[:1], testScope, 5); return ok },
+		"other top_n":  func() bool { _, ok := c.digest(papers, testScope, 3); return ok },
+	} {
+		if lookup() {
+			t.Errorf("Expected no cached digest for %s", name)
+		}
+	}
+}
+
+func TestNilCacheIsNoOp(t *testing.T) {
+	var c *Cache
+	digest := &Digest{Summaries: []PaperSummary{{Paper: samplePapers()[0], Summary: "s"}}}
+	c.apply(digest, samplePapers(), testScope, 5)
+	if cached := c.lookup(samplePapers(), testScope); cached != nil {
+		t.Errorf("Expected no cached summaries, got %+v", cached)
+	}
+	if _, ok := c.digest(samplePapers(), testScope, 5); ok {
+		t.Error("Expected no cached digest")
+	}
+}
+
+func TestCacheIgnoresCorruptEntries(t *testing.T) {
+	c, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCache returned error: %v", err)
+	}
+	p := samplePapers()[0]
+	if err := os.WriteFile(c.path(c.key(p, testScope)), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.get(p, testScope); ok {
+		t.Error("Expected a corrupt entry to be treated as missing")
+	}
+}
@@ -0,0 +1,40 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+// Chain tries summarizers in order and returns the first digest produced.
+// A summarizer is skipped when it fails for any reason, such as an API that
+// is still overloaded after retries or a response that cannot be parsed;
+// only a cancelled context ends the chain early.
+type Chain struct {
+	summarizers []Summarizer
+}
+
+func NewChain(summarizers ...Summarizer) *Chain {
+	return &Chain{summarizers: summarizers}
+}
+
+func (c *Chain) Summarize(ctx context.Context, papers []fetcher.Paper) (*Digest, error) {
+	var errs []error
+	for i, s := range c.summarizers {
+		digest, err := s.Summarize(ctx, papers)
+		if err == nil {
+			return digest, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		errs = append(errs, err)
+		if i < len(c.summarizers)-1 {
+			log.Printf("Summarizer %d of %d failed, falling back to the next: %v", i+1, len(c.summarizers), err)
+		}
+	}
+	return nil, fmt.Errorf("all %d summarizers failed: %w", len(c.summarizers), errors.Join(errs...))
+}
@@ -0,0 +1,97 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+type stubSummarizer struct {
+	err   error
+	calls int
+}
+
+func (s *stubSummarizer) Summarize(ctx context.Context, papers []fetcher.Paper) (*Digest, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &Digest{Overview: "stub", Backend: "stub"}, nil
+}
+
+func TestChainFallsBackOnUnparsableResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"content": [{"type": "text", "text": "Sorry, I cannot help with that."}]}`))
+	}))
+	defer ts.Close()
+
+	primary := &AnthropicSummarizer{
+		model:    "test-model",
+		topN:     5,
+		topic:    "AI",
+		language: "en",
+		client:   &http.Client{Transport: &rewriteTransport{testURL: ts.URL}},
+	}
+	chain := NewChain(primary, NewExtractiveSummarizer(5, []string{"AI"}, "en"))
+
+	digest, err := chain.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if digest.Backend != "extractive" {
+		t.Errorf("Expected the extractive fallback to produce the digest, got %q", digest.Backend)
+	}
+}
+
+func TestChainStopsAtFirstSuccess(t *testing.T) {
+	failing := &stubSummarizer{err: errors.New("anthropic: unexpected status 529")}
+	ok := &stubSummarizer{}
+	unused := &stubSummarizer{}
+
+	digest, err := NewChain(failing, ok, unused).Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if digest.Backend != "stub" {
+		t.Errorf("Expected the stub digest, got %+v", digest)
+	}
+	if failing.calls != 1 || ok.calls != 1 || unused.calls != 0 {
+		t.Errorf("Expected calls 1, 1, 0, got %d, %d, %d", failing.calls, ok.calls, unused.calls)
+	}
+}
+
+func TestChainAllFail(t *testing.T) {
+	chain := NewChain(
+		&stubSummarizer{err: errors.New("anthropic: unexpected status 529")},
+		&stubSummarizer{err: errors.New("openai: request failed")},
+	)
+
+	_, err := chain.Summarize(context.Background(), samplePapers())
+	if err == nil {
+		t.Fatal("Expected an error when every summarizer fails")
+	}
+	for _, want := range []string{"all 2 summarizers failed", "status 529", "openai: request failed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestChainStopsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	next := &stubSummarizer{}
+
+	_, err := NewChain(&stubSummarizer{err: context.Canceled}, next).Summarize(ctx, samplePapers())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+	if next.calls != 0 {
+		t.Errorf("Expected no fallback after cancellation, got %d calls", next.calls)
+	}
+}
@@ -0,0 +1,327 @@
+package summarizer
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/i18n"
+)
+
+// ExtractiveSummarizer builds digests without an LLM. Papers are ranked by
+// topic keyword relevance and recency, summaries are the best-scoring
+// sentences of each abstract and key points are condensed from the next
+// best. The output only depends on the input papers, so it also serves as a
+// deterministic stand-in in tests.
+type ExtractiveSummarizer struct {
+	topN     int
+	topic    string   // First topic, kept on the digest for backward compatibility
+	topics   []string // Multiple topics
+	language string
+}
+
+// Ranking and extraction parameters.
+const (
+	relevanceWeight   = 0.7
+	recencyWeight     = 0.3
+	recencyHalfLife   = 7 * 24 * time.Hour // Age, relative to the newest paper, that halves the recency score
+	summarySentences  = 2
+	maxKeyPoints      = 3
+	maxKeyPointWords  = 25
+	overviewTermCount = 5
+)
+
+var (
+	// sentenceEndRegex finds sentence boundaries: terminal punctuation
+	// followed by whitespace and an uppercase letter, digit or quote.
+	sentenceEndRegex = regexp.MustCompile(`[.!?]\s+["'(]?[A-Z0-9]`)
+	wordRegex        = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}-]*`)
+	// leadInRegex strips discourse markers from the start of key points.
+	leadInRegex = regexp.MustCompile(`(?i)^(in this (paper|work|study|article)|here|moreover|furthermore|additionally|in addition|finally|specifically|to this end|however|overall)\s*,?\s+`)
+	// cueWords mark sentences that state contributions or results.
+	cueWords = []string{"we propose", "we present", "we introduce", "we show", "we find", "we demonstrate", "outperform", "achieve", "improve", "state-of-the-art", "results"}
+)
+
+var stopwords = map[string]bool{
+	"a": true, "about": true, "above": true, "across": true, "after": true, "all": true, "also": true, "an": true,
+	"and": true, "any": true, "are": true, "as": true, "at": true, "based": true, "be": true, "been": true,
+	"between": true, "both": true, "but": true, "by": true, "can": true, "do": true, "does": true, "each": true,
+	"for": true, "from": true, "has": true, "have": true, "how": true, "however": true, "in": true, "into": true,
+	"is": true, "it": true, "its": true, "may": true, "more": true, "most": true, "new": true, "not": true,
+	"of": true, "on": true, "or": true, "our": true, "over": true, "paper": true, "such": true, "than": true,
+	"that": true, "the": true, "their": true, "them": true, "these": true, "this": true, "those": true,
+	"through": true, "to": true, "two": true, "under": true, "use": true, "using": true, "via": true, "we": true,
+	"what": true, "when": true, "which": true, "while": true, "with": true, "without": true, "work": true,
+}
+
+func NewExtractiveSummarizer(topN int, topics []string, language string) *ExtractiveSummarizer {
+	var topic string
+	if len(topics) > 0 {
+		topic = topics[0]
+	}
+	return &ExtractiveSummarizer{
+		topN:     topN,
+		topic:    topic,
+		topics:   topics,
+		language: language,
+	}
+}
+
+func (s *ExtractiveSummarizer) Summarize(ctx context.Context, papers []fetcher.Paper) (*Digest, error) {
+	if len(papers) == 0 {
+		digest := emptyDigest(s.topic, s.topics, s.language)
+		digest.Backend = s.Backend()
+		return digest, nil
+	}
+
+	keywords := topicKeywords(s.topics)
+	ranked := rankPapers(papers, keywords)
+	if len(ranked) > s.topN {
+		ranked = ranked[:s.topN]
+	}
+
+	digest := &Digest{
+		Topic:    s.topic, // For backward compatibility
+		Topics:   s.topics,
+		Date:     time.Now(),
+		Backend:  s.Backend(),
+		Language: s.language,
+	}
+	selected := make([]fetcher.Paper, len(ranked))
+	for i, idx := range ranked {
+		p := papers[idx]
+		selected[i] = p
+		summary, keyPoints := extractSummary(p.Abstract, keywords)
+		digest.Summaries = append(digest.Summaries, PaperSummary{
+			Paper:     p,
+			Summary:   summary,
+			KeyPoints: keyPoints,
+		})
+	}
+	digest.Overview = s.overview(len(papers), selected, keywords)
+
+	return digest, nil
+}
+
+// Backend names the summarizer, "extractive".
+func (s *ExtractiveSummarizer) Backend() string {
+	return "extractive"
+}
+
+// topicKeywords returns the lowercase content words of the topics.
+func topicKeywords(topics []string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, topic := range topics {
+		for _, w := range contentWords(topic) {
+			if !seen[w] {
+				seen[w] = true
+				keywords = append(keywords, w)
+			}
+		}
+	}
+	return keywords
+}
+
+// contentWords returns the lowercase words of s that are not stopwords.
+func contentWords(s string) []string {
+	var words []string
+	for _, w := range wordRegex.FindAllString(strings.ToLower(s), -1) {
+		if !stopwords[w] && len(w) > 1 {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// rankPapers returns paper indexes ordered by a weighted score of keyword
+// relevance and recency, best first. Ties keep the input order.
+func rankPapers(papers []fetcher.Paper, keywords []string) []int {
+	var newest time.Time
+	for _, p := range papers {
+		if p.Published.After(newest) {
+			newest = p.Published
+		}
+	}
+
+	scores := make([]float64, len(papers))
+	for i, p := range papers {
+		relevance := keywordRelevance(p.Title, keywords)*2 + keywordRelevance(p.Abstract, keywords)
+		recency := 0.0
+		if !p.Published.IsZero() {
+			age := newest.Sub(p.Published)
+			recency = math.Exp2(-float64(age) / float64(recencyHalfLife))
+		}
+		scores[i] = relevanceWeight*relevance/3 + recencyWeight*recency
+	}
+
+	order := make([]int, len(papers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	return order
+}
+
+// keywordRelevance returns the fraction of keywords that occur in text.
+func keywordRelevance(text string, keywords []string) float64 {
+	if len(keywords) == 0 {
+		return 0
+	}
+	words := make(map[string]bool)
+	for _, w := range contentWords(text) {
+		words[w] = true
+	}
+	hits := 0
+	for _, kw := range keywords {
+		if words[kw] || words[strings.TrimSuffix(kw, "s")] || words[kw+"s"] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(keywords))
+}
+
+// splitSentences splits text into sentences, keeping their punctuation.
+func splitSentences(text string) []string {
+	text = strings.Join(strings.Fields(text), " ")
+	var sentences []string
+	start := 0
+	for _, m := range sentenceEndRegex.FindAllStringIndex(text, -1) {
+		end := m[0] + 1
+		sentences = append(sentences, strings.TrimSpace(text[start:end]))
+		start = m[1] - 1
+		// Keep an opening quote or parenthesis with the next sentence.
+		for start > end && !unicode.IsSpace(rune(text[start-1])) {
+			start--
+		}
+	}
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+// extractSummary returns the best summarySentences sentences of an abstract
+// in their original order, and key points condensed from the next best.
+func extractSummary(abstract string, keywords []string) (string, []string) {
+	sentences := splitSentences(abstract)
+	if len(sentences) == 0 {
+		return "", nil
+	}
+
+	scores := make([]float64, len(sentences))
+	for i, sent := range sentences {
+		lower := strings.ToLower(sent)
+		score := keywordRelevance(sent, keywords)
+		for _, cue := range cueWords {
+			if strings.Contains(lower, cue) {
+				score += 0.5
+				break
+			}
+		}
+		if strings.ContainsAny(sent, "0123456789") {
+			score += 0.25 // Quantitative results
+		}
+		if i == 0 {
+			score += 0.3 // Abstracts usually open with the problem or contribution
+		}
+		if n := len(strings.Fields(sent)); n < 6 {
+			score -= 0.5
+		}
+		scores[i] = score
+	}
+
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	n := min(summarySentences, len(order))
+	chosen := append([]int(nil), order[:n]...)
+	sort.Ints(chosen)
+	parts := make([]string, len(chosen))
+	for i, idx := range chosen {
+		parts[i] = sentences[idx]
+	}
+	summary := strings.Join(parts, " ")
+
+	var keyPoints []string
+	for _, idx := range order[n:] {
+		if len(keyPoints) == maxKeyPoints {
+			break
+		}
+		keyPoints = append(keyPoints, keyPhrase(sentences[idx]))
+	}
+	if len(keyPoints) == 0 {
+		// Short abstracts: condense the summary sentences instead.
+		for _, idx := range chosen {
+			keyPoints = append(keyPoints, keyPhrase(sentences[idx]))
+		}
+	}
+	return summary, keyPoints
+}
+
+// keyPhrase condenses a sentence into a key point: the lead-in is dropped,
+// the first letter capitalized and long sentences cut at maxKeyPointWords.
+func keyPhrase(sentence string) string {
+	phrase := leadInRegex.ReplaceAllString(sentence, "")
+	phrase = strings.TrimRight(phrase, ".!? ")
+	words := strings.Fields(phrase)
+	if len(words) > maxKeyPointWords {
+		phrase = strings.TrimRight(strings.Join(words[:maxKeyPointWords], " "), ",;:") + " …"
+	}
+	if r := []rune(phrase); len(r) > 0 {
+		r[0] = unicode.ToUpper(r[0])
+		phrase = string(r)
+	}
+	return phrase
+}
+
+// overview describes the selection and the most frequent terms in the titles
+// and abstracts of the selected papers.
+func (s *ExtractiveSummarizer) overview(total int, selected []fetcher.Paper, keywords []string) string {
+	isKeyword := make(map[string]bool, len(keywords))
+	for _, kw := range keywords {
+		isKeyword[kw] = true
+	}
+	counts := make(map[string]int)
+	for _, p := range selected {
+		for _, w := range contentWords(p.Title + " " + p.Abstract) {
+			if !isKeyword[w] && len(w) > 3 {
+				counts[w]++
+			}
+		}
+	}
+	terms := make([]string, 0, len(counts))
+	for w, c := range counts {
+		if c > 1 {
+			terms = append(terms, w)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if counts[terms[i]] != counts[terms[j]] {
+			return counts[terms[i]] > counts[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > overviewTermCount {
+		terms = terms[:overviewTermCount]
+	}
+
+	loc := i18n.Get(s.language)
+	text := loc.T("extractive_overview", strings.Join(s.topics, ", "), total, len(selected))
+	if len(terms) > 0 {
+		text += loc.T("extractive_terms", strings.Join(terms, ", "))
+	}
+	return text
+}
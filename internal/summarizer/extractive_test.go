@@ -0,0 +1,158 @@
+package summarizer
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+func extractivePapers() []fetcher.Paper {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	return []fetcher.Paper{
+		{
+			Title:     "A Survey of Protein Folding",
+			Abstract:  "Protein folding remains a central problem in biology. We review physical models of folding.",
+			Published: day,
+		},
+		{
+			Title: "Scaling Laws for Language Models",
+			Abstract: "Large language models keep improving with scale. In this paper, we study how loss depends on model size and data. " +
+				"We show that language model loss follows a power law across 7 orders of magnitude. " +
+				"The experiments use a fixed tokenizer. Moreover, larger models are more sample efficient than smaller ones.",
+			Published: day.AddDate(0, 0, -3),
+		},
+		{
+			Title:     "Language Models as Tutors",
+			Abstract:  "We evaluate language models as tutors for students. Tutoring quality improves with dialogue context.",
+			Published: day.AddDate(0, 0, -1),
+		},
+	}
+}
+
+func TestExtractiveSummarizeRanksByRelevanceAndRecency(t *testing.T) {
+	s := NewExtractiveSummarizer(2, []string{"large language models"}, "en")
+
+	digest, err := s.Summarize(context.Background(), extractivePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+
+	if len(digest.Summaries) != 2 {
+		t.Fatalf("Expected 2 summaries, got %d", len(digest.Summaries))
+	}
+	// Both language model papers outrank the newer but unrelated one; the
+	// scaling paper matches all keywords in its abstract.
+	if got := digest.Summaries[0].Paper.Title; got != "Scaling Laws for Language Models" {
+		t.Errorf("Expected the scaling paper first, got %q", got)
+	}
+	if got := digest.Summaries[1].Paper.Title; got != "Language Models as Tutors" {
+		t.Errorf("Expected the tutoring paper second, got %q", got)
+	}
+	if !strings.Contains(digest.Overview, "top 2 of 3 papers on large language models") {
+		t.Errorf("Unexpected overview: %q", digest.Overview)
+	}
+}
+
+func TestExtractiveSummarizeSentences(t *testing.T) {
+	s := NewExtractiveSummarizer(1, []string{"large language models"}, "en")
+
+	digest, err := s.Summarize(context.Background(), extractivePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+
+	ps := digest.Summaries[0]
+	wantSummary := "Large language models keep improving with scale. We show that language model loss follows a power law across 7 orders of magnitude."
+	if ps.Summary != wantSummary {
+		t.Errorf("Expected summary %q, got %q", wantSummary, ps.Summary)
+	}
+	wantKeyPoints := []string{
+		"We study how loss depends on model size and data",
+		"Larger models are more sample efficient than smaller ones",
+		"The experiments use a fixed tokenizer",
+	}
+	if !reflect.DeepEqual(ps.KeyPoints, wantKeyPoints) {
+		t.Errorf("Expected key points %q, got %q", wantKeyPoints, ps.KeyPoints)
+	}
+}
+
+func TestExtractiveSummarizeIsDeterministic(t *testing.T) {
+	s := NewExtractiveSummarizer(3, []string{"language models", "protein folding"}, "ja")
+
+	first, err := s.Summarize(context.Background(), extractivePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		again, err := s.Summarize(context.Background(), extractivePapers())
+		if err != nil {
+			t.Fatalf("Summarize returned error: %v", err)
+		}
+		again.Date = first.Date
+		if !reflect.DeepEqual(first, again) {
+			t.Fatalf("Expected identical digests, got %+v and %+v", first, again)
+		}
+	}
+	if !strings.Contains(first.Overview, "上位3件") {
+		t.Errorf("Expected a Japanese overview, got %q", first.Overview)
+	}
+}
+
+func TestExtractiveSummarizeShortAbstract(t *testing.T) {
+	s := NewExtractiveSummarizer(5, []string{"AI"}, "en")
+
+	digest, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if len(digest.Summaries) != 2 {
+		t.Fatalf("Expected 2 summaries, got %d", len(digest.Summaries))
+	}
+	ps := digest.Summaries[0]
+	if ps.Paper.Title != "Paper One" || ps.Summary != "Abstract one about AI." {
+		t.Errorf("Unexpected summary: %+v", ps)
+	}
+	if len(ps.KeyPoints) != 1 || ps.KeyPoints[0] != "Abstract one about AI" {
+		t.Errorf("Expected the sentence condensed into a key point, got %q", ps.KeyPoints)
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	text := "First sentence here.  Second one (with 3.5% gains)! \"Quoted\" third? 4 models were tested."
+	want := []string{
+		"First sentence here.",
+		"Second one (with 3.5% gains)!",
+		"\"Quoted\" third?",
+		"4 models were tested.",
+	}
+	if got := splitSentences(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestExtractiveSummarizeLocalizesOverview(t *testing.T) {
+	s := NewExtractiveSummarizer(2, []string{"large language models"}, "fr")
+
+	digest, err := s.Summarize(context.Background(), extractivePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if digest.Language != "fr" {
+		t.Errorf("Expected the digest language to be recorded, got %q", digest.Language)
+	}
+	if !strings.HasPrefix(digest.Overview, "Les 2 articles les plus pertinents parmi 3 sur large language models") {
+		t.Errorf("Unexpected overview: %q", digest.Overview)
+	}
+
+	empty, err := s.Summarize(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if empty.Overview != "Aucun article trouvé pour le ou les sujets : large language models." || empty.Language != "fr" {
+		t.Errorf("Unexpected empty digest: %q (%s)", empty.Overview, empty.Language)
+	}
+}
@@ -0,0 +1,175 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+// Tolerant parsing for the sloppier JSON of small local models.
+
+var (
+	thinkBlockRegex    = regexp.MustCompile(`(?s)<think>.*?</think>`)
+	trailingCommaRegex = regexp.MustCompile(`,(\s*[}\]])`)
+	smartQuotes        = strings.NewReplacer("“", `"`, "”", `"`)
+)
+
+// parseDigestLenient parses a response like parseDigest, but also accepts
+// text around the JSON object, reasoning blocks, trailing commas, output cut
+// off mid-object, indexes given as strings, and key points given as a single
+// string or under a differently cased key.
+func parseDigestLenient(body string, papers []fetcher.Paper, topic string, topics []string) (*Digest, error) {
+	var ld lenientDigest
+	if err := decodeLenient(body, &ld); err != nil {
+		return nil, err
+	}
+
+	dj := digestJSON{Overview: ld.Overview}
+	for _, ls := range ld.Summaries {
+		dj.Summaries = append(dj.Summaries, summaryJSON(ls))
+	}
+	return newDigest(dj, papers, topic, topics), nil
+}
+
+// decodeLenient decodes the first JSON object in an LLM response into v,
+// after removing reasoning blocks, smart quotes and trailing commas.
+func decodeLenient(body string, v any) error {
+	obj := extractJSONObject(smartQuotes.Replace(thinkBlockRegex.ReplaceAllString(body, "")))
+	if obj == "" {
+		return fmt.Errorf("failed to parse LLM JSON: no JSON object found\nraw response: %s", body)
+	}
+	obj = trailingCommaRegex.ReplaceAllString(obj, "$1")
+
+	if err := json.Unmarshal([]byte(obj), v); err != nil {
+		return fmt.Errorf("failed to parse LLM JSON: %w\nraw response: %s", err, body)
+	}
+	return nil
+}
+
+// extractJSONObject returns the first JSON object in s. When the object is
+// cut off, the open strings, arrays and objects are closed.
+func extractJSONObject(s string) string {
+	start := strings.IndexByte(s, '{')
+	if start < 0 {
+		return ""
+	}
+
+	var stack []byte
+	inString, escaped := false, false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				return s[start : i+1]
+			}
+		}
+	}
+
+	// Truncated: drop a dangling partial value and close what is open.
+	obj := strings.TrimRight(s[start:], " \t\r\n")
+	if inString {
+		obj += `"`
+	}
+	obj = strings.TrimRight(obj, ",: \t\r\n")
+	for i := len(stack) - 1; i >= 0; i-- {
+		obj += string(stack[i])
+	}
+	return obj
+}
+
+type lenientDigest struct {
+	Overview  string           `json:"overview"`
+	Summaries []lenientSummary `json:"summaries"`
+}
+
+// lenientSummary has the fields of summaryJSON, decoded from whichever
+// spelling and type the model chose.
+type lenientSummary struct {
+	Index     int
+	Summary   string
+	KeyPoints []string
+}
+
+func (ls *lenientSummary) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	fields := make(map[string]json.RawMessage, len(raw))
+	for k, v := range raw {
+		fields[strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(k))] = v
+	}
+
+	for _, key := range []string{"index", "paper", "papernumber", "id"} {
+		if v, ok := fields[key]; ok {
+			ls.Index = lenientInt(v)
+			break
+		}
+	}
+	json.Unmarshal(fields["summary"], &ls.Summary)
+	for _, key := range []string{"keypoints", "points", "highlights"} {
+		if v, ok := fields[key]; ok {
+			ls.KeyPoints = lenientStrings(v)
+			break
+		}
+	}
+	return nil
+}
+
+// lenientInt decodes a number or a string holding one, such as "2" or "Paper 2".
+func lenientInt(v json.RawMessage) int {
+	var f float64
+	if json.Unmarshal(v, &f) == nil {
+		return int(f)
+	}
+	var s string
+	if json.Unmarshal(v, &s) == nil {
+		n, _ := strconv.Atoi(strings.TrimSpace(strings.TrimLeft(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ#. ")))
+		return n
+	}
+	return 0
+}
+
+// lenientStrings decodes a list of strings, or a single string with one point
+// per line.
+func lenientStrings(v json.RawMessage) []string {
+	var list []string
+	if json.Unmarshal(v, &list) == nil {
+		return list
+	}
+	var s string
+	if json.Unmarshal(v, &s) != nil {
+		return nil
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "-*• \t")); line != "" {
+			list = append(list, line)
+		}
+	}
+	return list
+}
@@ -0,0 +1,405 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+// Completer is a model that answers a single prompt with text. The LLM-backed
+// summarizers implement it, which lets MapReduceSummarizer drive them.
+type Completer interface {
+	Complete(ctx context.Context, prompt string, maxTokens int) (string, error)
+	Backend() string
+}
+
+// MapReduceSummarizer summarizes candidate pools too large for one prompt in
+// two phases. In the map phase, batches of papers are scored and summarized
+// in parallel. In the reduce phase, a final call sees only the best-scored
+// candidates, with their summaries instead of abstracts, selects the topN
+// papers and writes the overview.
+type MapReduceSummarizer struct {
+	completer       Completer
+	topN            int
+	topic           string   // First topic, kept on the digest for backward compatibility
+	topics          []string // Multiple topics
+	language        string
+	batchSize       int // Papers per map call
+	concurrency     int // Map calls in flight at once
+	shortlist       int // Candidates passed to the reduce call; 0 means 3 × topN
+	mapMaxTokens    int
+	reduceMaxTokens int
+	cache           *Cache   // Optional per-paper summary cache
+	prompts         *Prompts // Prompt templates; nil uses the built-in ones
+}
+
+func NewMapReduceSummarizer(completer Completer, topN int, topics []string, language string) *MapReduceSummarizer {
+	var topic string
+	if len(topics) > 0 {
+		topic = topics[0]
+	}
+	return &MapReduceSummarizer{
+		completer:       completer,
+		topN:            topN,
+		topic:           topic,
+		topics:          topics,
+		language:        language,
+		batchSize:       20,
+		concurrency:     4,
+		mapMaxTokens:    4096,
+		reduceMaxTokens: 2048,
+	}
+}
+
+// SetBatching sets the number of papers per map call and how many map calls
+// run at once. Zero values keep the defaults of 20 and 4.
+func (s *MapReduceSummarizer) SetBatching(batchSize, concurrency int) {
+	if batchSize > 0 {
+		s.batchSize = batchSize
+	}
+	if concurrency > 0 {
+		s.concurrency = concurrency
+	}
+}
+
+// SetShortlist sets the number of best-scored candidates passed to the
+// reduce call. Zero passes three times topN.
+func (s *MapReduceSummarizer) SetShortlist(n int) {
+	s.shortlist = n
+}
+
+// SetTokenBudgets sets max_tokens for each map call and for the reduce call.
+// Zero values keep the defaults of 4096 and 2048.
+func (s *MapReduceSummarizer) SetTokenBudgets(mapMaxTokens, reduceMaxTokens int) {
+	if mapMaxTokens > 0 {
+		s.mapMaxTokens = mapMaxTokens
+	}
+	if reduceMaxTokens > 0 {
+		s.reduceMaxTokens = reduceMaxTokens
+	}
+}
+
+// SetCache makes the map phase skip papers scored and summarized before.
+func (s *MapReduceSummarizer) SetCache(cache *Cache) {
+	s.cache = cache
+}
+
+// SetPrompts replaces the built-in map and reduce prompt templates.
+func (s *MapReduceSummarizer) SetPrompts(prompts *Prompts) {
+	s.prompts = prompts
+}
+
+// cacheScope keys cached candidates.
+func (s *MapReduceSummarizer) cacheScope() cacheScope {
+	return cacheScope{backend: s.Backend(), language: s.language, promptVersion: s.prompts.Version()}
+}
+
+// Backend names the underlying model and the mode.
+func (s *MapReduceSummarizer) Backend() string {
+	return s.completer.Backend() + " (map-reduce)"
+}
+
+// candidate is a paper scored and summarized in the map phase.
+type candidate struct {
+	paper     fetcher.Paper
+	score     int
+	summary   string
+	keyPoints []string
+}
+
+// mapResultJSON is the expected JSON structure of a map call.
+type mapResultJSON struct {
+	Papers []mapPaperJSON `json:"papers"`
+}
+
+type mapPaperJSON struct {
+	lenientSummary
+	Score int
+}
+
+func (mp *mapPaperJSON) UnmarshalJSON(data []byte) error {
+	if err := mp.lenientSummary.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var raw struct {
+		Score json.RawMessage `json:"score"`
+	}
+	json.Unmarshal(data, &raw)
+	mp.Score = lenientInt(raw.Score)
+	return nil
+}
+
+// reduceJSON is the expected JSON structure of the reduce call.
+type reduceJSON struct {
+	Overview string            `json:"overview"`
+	Selected []json.RawMessage `json:"selected"`
+}
+
+func (s *MapReduceSummarizer) Summarize(ctx context.Context, papers []fetcher.Paper) (*Digest, error) {
+	if len(papers) == 0 {
+		digest := emptyDigest(s.topic, s.topics, s.language)
+		digest.Backend = s.Backend()
+		return digest, nil
+	}
+
+	if digest, ok := s.cache.digest(papers, s.cacheScope(), s.topN); ok {
+		digest.Topic, digest.Topics = s.topic, s.topics
+		digest.Backend = s.Backend()
+		digest.PromptVersion = s.prompts.Version()
+		digest.Language = s.language
+		return digest, nil
+	}
+	candidates, err := s.candidates(ctx, papers)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("map-reduce: no paper was scored in the map phase")
+	}
+
+	// Best scores first; ties keep the fetch order.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	n := s.shortlist
+	if n <= 0 {
+		n = 3 * s.topN
+	}
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+
+	digest, err := s.reduce(ctx, len(papers), candidates)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.storeDigest(digest, papers, s.cacheScope(), s.topN)
+	return digest, nil
+}
+
+// candidates returns the scored and summarized papers in fetch order. Papers
+// found in the cache skip the map phase; the others are mapped and stored.
+func (s *MapReduceSummarizer) candidates(ctx context.Context, papers []fetcher.Paper) ([]candidate, error) {
+	cached := s.cache.lookup(papers, s.cacheScope())
+
+	var uncached []fetcher.Paper
+	for i, p := range papers {
+		if cs, ok := cached[i]; !ok || cs.Score == nil {
+			uncached = append(uncached, p)
+		}
+	}
+	if len(uncached) == len(papers) {
+		mapped, err := s.mapPhase(ctx, papers)
+		if err != nil {
+			return nil, err
+		}
+		s.storeCandidates(mapped)
+		return mapped, nil
+	}
+
+	mapped := make(map[string]candidate)
+	if len(uncached) > 0 {
+		batch, err := s.mapPhase(ctx, uncached)
+		if err != nil {
+			return nil, err
+		}
+		s.storeCandidates(batch)
+		for _, c := range batch {
+			mapped[paperKey(c.paper)] = c
+		}
+	}
+
+	var candidates []candidate
+	for i, p := range papers {
+		if cs, ok := cached[i]; ok && cs.Score != nil {
+			candidates = append(candidates, candidate{
+				paper:     p,
+				score:     *cs.Score,
+				summary:   cs.Summary,
+				keyPoints: cs.KeyPoints,
+			})
+		} else if c, ok := mapped[paperKey(p)]; ok {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+// storeCandidates caches mapped candidates with their scores.
+func (s *MapReduceSummarizer) storeCandidates(candidates []candidate) {
+	for _, c := range candidates {
+		score := c.score
+		s.cache.store(c.paper, s.cacheScope(), cachedSummary{
+			Summary:   c.summary,
+			KeyPoints: c.keyPoints,
+			Score:     &score,
+		})
+	}
+}
+
+// mapPhase scores and summarizes papers in batches, running up to
+// concurrency batches at once. The first failing batch cancels the others.
+func (s *MapReduceSummarizer) mapPhase(ctx context.Context, papers []fetcher.Paper) ([]candidate, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	numBatches := (len(papers) + s.batchSize - 1) / s.batchSize
+	results := make([][]candidate, numBatches)
+	sem := make(chan struct{}, s.concurrency)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for b := 0; b < numBatches; b++ {
+		start := b * s.batchSize
+		end := min(start+s.batchSize, len(papers))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			batch, err := s.mapBatch(ctx, papers[start:end], len(papers))
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("map-reduce: batch %d of %d: %w", b+1, numBatches, err)
+					cancel()
+				}
+				mu.Unlock()
+				return
+			}
+			results[b] = batch
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var candidates []candidate
+	for _, batch := range results {
+		candidates = append(candidates, batch...)
+	}
+	return candidates, nil
+}
+
+// mapBatch scores and summarizes one batch of papers. Papers the model left
+// out of its answer are dropped.
+func (s *MapReduceSummarizer) mapBatch(ctx context.Context, batch []fetcher.Paper, total int) ([]candidate, error) {
+	prompt, err := s.mapPrompt(batch, total)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.completer.Complete(ctx, prompt, s.mapMaxTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	var mr mapResultJSON
+	if err := decodeLenient(body, &mr); err != nil {
+		return nil, err
+	}
+
+	var candidates []candidate
+	seen := make(map[int]bool)
+	for _, mp := range mr.Papers {
+		idx := mp.Index - 1 // Convert from 1-based to 0-based
+		if idx < 0 || idx >= len(batch) || seen[idx] {
+			continue
+		}
+		seen[idx] = true
+		candidates = append(candidates, candidate{
+			paper:     batch[idx],
+			score:     min(max(mp.Score, 0), 10),
+			summary:   mp.Summary,
+			keyPoints: mp.KeyPoints,
+		})
+	}
+	return candidates, nil
+}
+
+// reduce asks for the final selection and overview. When the answer selects
+// no valid candidate, the best-scored ones are used.
+func (s *MapReduceSummarizer) reduce(ctx context.Context, total int, shortlist []candidate) (*Digest, error) {
+	prompt, err := s.reducePrompt(shortlist, total)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.completer.Complete(ctx, prompt, s.reduceMaxTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	var rj reduceJSON
+	if err := decodeLenient(body, &rj); err != nil {
+		return nil, fmt.Errorf("map-reduce: %w", err)
+	}
+
+	var selected []candidate
+	seen := make(map[int]bool)
+	for _, raw := range rj.Selected {
+		idx := lenientInt(raw) - 1
+		if idx < 0 || idx >= len(shortlist) || seen[idx] || len(selected) == s.topN {
+			continue
+		}
+		seen[idx] = true
+		selected = append(selected, shortlist[idx])
+	}
+	if len(selected) == 0 {
+		selected = shortlist[:min(s.topN, len(shortlist))]
+	}
+
+	digest := &Digest{
+		Topic:         s.topic, // For backward compatibility
+		Topics:        s.topics,
+		Date:          time.Now(),
+		Overview:      rj.Overview,
+		Backend:       s.Backend(),
+		PromptVersion: s.prompts.Version(),
+		Language:      s.language,
+	}
+	for _, c := range selected {
+		digest.Summaries = append(digest.Summaries, PaperSummary{
+			Paper:     c.paper,
+			Summary:   c.summary,
+			KeyPoints: c.keyPoints,
+		})
+	}
+	return digest, nil
+}
+
+// mapPrompt asks for a score, summary and key points of each paper of a
+// batch out of total papers.
+func (s *MapReduceSummarizer) mapPrompt(batch []fetcher.Paper, total int) (string, error) {
+	data := promptData{Total: total, Topics: s.topics, MultiTopic: len(s.topics) > 1}
+	for i, p := range batch {
+		data.Papers = append(data.Papers, promptPaper{Paper: p, Number: i + 1})
+	}
+	return s.prompts.render(mapPromptKind, s.language, data)
+}
+
+// reducePrompt asks for the topN best candidates of the shortlist, out of
+// total papers, and the overview.
+func (s *MapReduceSummarizer) reducePrompt(shortlist []candidate, total int) (string, error) {
+	data := promptData{Total: total, Topics: s.topics, TopN: s.topN, MultiTopic: len(s.topics) > 1}
+	for i, c := range shortlist {
+		data.Candidates = append(data.Candidates, promptCandidate{Paper: c.paper, Number: i + 1, Score: c.score, Summary: c.summary})
+	}
+	return s.prompts.render(reducePromptKind, s.language, data)
+}
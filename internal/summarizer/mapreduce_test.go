@@ -0,0 +1,212 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+)
+
+// fakeCompleter answers prompts with respond and records concurrency.
+type fakeCompleter struct {
+	respond func(prompt string, maxTokens int) (string, error)
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *fakeCompleter) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	time.Sleep(10 * time.Millisecond) // Let batches overlap
+	return f.respond(prompt, maxTokens)
+}
+
+func (f *fakeCompleter) Backend() string {
+	return "fake/model"
+}
+
+var titleScoreRegex = regexp.MustCompile(`Title: P(\d+)`)
+
+// scoreByTitle answers a map prompt for papers titled "P<n>", scoring each
+// paper n.
+func scoreByTitle(prompt string) string {
+	var items []string
+	for i, m := range titleScoreRegex.FindAllStringSubmatch(prompt, -1) {
+		items = append(items, fmt.Sprintf(`{"index": %d, "score": %s, "summary": "Summary of P%s.", "key_points": ["k"]}`, i+1, m[1], m[1]))
+	}
+	return `{"papers": [` + strings.Join(items, ", ") + `]}`
+}
+
+func numberedPapers(n int) []fetcher.Paper {
+	papers := make([]fetcher.Paper, n)
+	for i := range papers {
+		papers[i] = fetcher.Paper{Title: fmt.Sprintf("P%d", i+1), Abstract: "Abstract.", Category: "cs.AI"}
+	}
+	return papers
+}
+
+func TestMapReduceSummarize(t *testing.T) {
+	var mu sync.Mutex
+	var mapCalls int
+	var reducePrompt string
+	fc := &fakeCompleter{respond: func(prompt string, maxTokens int) (string, error) {
+		if strings.Contains(prompt, "--- Candidate") {
+			if maxTokens != 500 {
+				t.Errorf("Expected reduce budget 500, got %d", maxTokens)
+			}
+			reducePrompt = prompt
+			return `{"overview": "Reduced overview.", "selected": [2, "1", 2, 9]}`, nil
+		}
+		if maxTokens != 1000 {
+			t.Errorf("Expected map budget 1000, got %d", maxTokens)
+		}
+		mu.Lock()
+		mapCalls++
+		mu.Unlock()
+		return scoreByTitle(prompt), nil
+	}}
+
+	s := NewMapReduceSummarizer(fc, 2, []string{"AI"}, "en")
+	s.SetBatching(3, 2)
+	s.SetShortlist(3)
+	s.SetTokenBudgets(1000, 500)
+
+	digest, err := s.Summarize(context.Background(), numberedPapers(8))
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+
+	if mapCalls != 3 {
+		t.Errorf("Expected 3 map calls for 8 papers in batches of 3, got %d", mapCalls)
+	}
+	if fc.maxInFlight > 2 {
+		t.Errorf("Expected at most 2 concurrent calls, got %d", fc.maxInFlight)
+	}
+
+	// The shortlist holds the three best scores: P8, P7 and P6.
+	for _, want := range []string{"Title: P8", "Title: P7", "Title: P6", "Summary: Summary of P8."} {
+		if !strings.Contains(reducePrompt, want) {
+			t.Errorf("Expected %q in the reduce prompt", want)
+		}
+	}
+	if strings.Contains(reducePrompt, "Title: P5") || strings.Contains(reducePrompt, "Abstract") {
+		t.Error("Expected only shortlisted summaries, without abstracts, in the reduce prompt")
+	}
+
+	if digest.Overview != "Reduced overview." || digest.Backend != "fake/model (map-reduce)" {
+		t.Errorf("Unexpected digest: %+v", digest)
+	}
+	if len(digest.Summaries) != 2 || digest.Summaries[0].Paper.Title != "P7" || digest.Summaries[1].Paper.Title != "P8" {
+		t.Fatalf("Expected the selected P7 and P8 in order, got %+v", digest.Summaries)
+	}
+	if digest.Summaries[0].Summary != "Summary of P7." {
+		t.Errorf("Expected the map summary, got %q", digest.Summaries[0].Summary)
+	}
+}
+
+func TestMapReduceFallsBackToScoresWithoutSelection(t *testing.T) {
+	fc := &fakeCompleter{respond: func(prompt string, maxTokens int) (string, error) {
+		if strings.Contains(prompt, "--- Candidate") {
+			return `{"overview": "o", "selected": []}`, nil
+		}
+		return scoreByTitle(prompt), nil
+	}}
+
+	s := NewMapReduceSummarizer(fc, 2, []string{"AI"}, "en")
+	digest, err := s.Summarize(context.Background(), numberedPapers(4))
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if len(digest.Summaries) != 2 || digest.Summaries[0].Paper.Title != "P4" || digest.Summaries[1].Paper.Title != "P3" {
+		t.Errorf("Expected the two best-scored papers, got %+v", digest.Summaries)
+	}
+}
+
+func TestMapReduceBatchError(t *testing.T) {
+	fc := &fakeCompleter{respond: func(prompt string, maxTokens int) (string, error) {
+		if strings.Contains(prompt, "Title: P4") {
+			return "", errors.New("fake: unexpected status 529")
+		}
+		return scoreByTitle(prompt), nil
+	}}
+
+	s := NewMapReduceSummarizer(fc, 2, []string{"AI"}, "en")
+	s.SetBatching(3, 1)
+
+	_, err := s.Summarize(context.Background(), numberedPapers(9))
+	if err == nil || !strings.Contains(err.Error(), "batch 2 of 3") || !strings.Contains(err.Error(), "529") {
+		t.Errorf("Expected the batch 2 error, got: %v", err)
+	}
+}
+
+func TestMapReduceSkipsCachedPapers(t *testing.T) {
+	var mu sync.Mutex
+	var mapped []string
+	fc := &fakeCompleter{respond: func(prompt string, maxTokens int) (string, error) {
+		if strings.Contains(prompt, "--- Candidate") {
+			return `{"overview": "o", "selected": [1, 2]}`, nil
+		}
+		mu.Lock()
+		for _, m := range titleScoreRegex.FindAllString(prompt, -1) {
+			mapped = append(mapped, m)
+		}
+		mu.Unlock()
+		return scoreByTitle(prompt), nil
+	}}
+	cache, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCache returned error: %v", err)
+	}
+
+	s := NewMapReduceSummarizer(fc, 2, []string{"AI"}, "en")
+	s.SetCache(cache)
+	if _, err := s.Summarize(context.Background(), numberedPapers(3)); err != nil {
+		t.Fatalf("First Summarize returned error: %v", err)
+	}
+
+	mapped = nil
+	digest, err := s.Summarize(context.Background(), numberedPapers(5))
+	if err != nil {
+		t.Fatalf("Second Summarize returned error: %v", err)
+	}
+	if len(mapped) != 2 || mapped[0] != "Title: P4" || mapped[1] != "Title: P5" {
+		t.Errorf("Expected only the new papers P4 and P5 to be mapped, got %v", mapped)
+	}
+	if len(digest.Summaries) != 2 || digest.Summaries[0].Paper.Title != "P5" || digest.Summaries[1].Summary != "Summary of P4." {
+		t.Errorf("Expected P5 and P4 from the merged candidates, got %+v", digest.Summaries)
+	}
+}
+
+func TestMapReducePromptsAskForLanguage(t *testing.T) {
+	es := NewMapReduceSummarizer(&fakeCompleter{}, 2, []string{"AI"}, "es")
+	if p, err := es.mapPrompt(numberedPapers(2), 2); err != nil || !strings.HasSuffix(p, "\n\nWrite the summaries and key points in Spanish.") {
+		t.Errorf("Expected a language instruction at the end of the map prompt, got %q, %v", p, err)
+	}
+	shortlist := []candidate{{paper: numberedPapers(1)[0], score: 5, summary: "s"}}
+	if p, err := es.reducePrompt(shortlist, 2); err != nil || !strings.HasSuffix(p, "\n\nWrite the overview in Spanish.") {
+		t.Errorf("Expected a language instruction at the end of the reduce prompt, got %q, %v", p, err)
+	}
+
+	for _, language := range []string{"en", "ja"} {
+		s := NewMapReduceSummarizer(&fakeCompleter{}, 2, []string{"AI"}, language)
+		if p, _ := s.mapPrompt(numberedPapers(2), 2); strings.Contains(p, "Write the summaries") {
+			t.Errorf("Expected no language instruction for %s", language)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/retry"
+)
+
+// OllamaSummarizer summarizes papers with a model served by a local Ollama
+// server, so that no abstract leaves the machine. Responses are requested in
+// JSON format and parsed leniently.
+type OllamaSummarizer struct {
+	baseURL     string // e.g. "http://localhost:11434"
+	endpoint    string // chat | generate
+	model       string
+	numCtx      int     // Context window in tokens; 0 keeps the model default
+	temperature float64 // Sampling temperature; negative keeps the model default
+	topN        int
+	topic       string   // First topic, kept on the digest for backward compatibility
+	topics      []string // Multiple topics
+	language    string
+	client      *http.Client
+	retryConfig retry.Config
+	cache       *Cache   // Optional per-paper summary cache
+	prompts     *Prompts // Digest prompt templates; nil uses the built-in ones
+}
+
+func NewOllamaSummarizer(baseURL, model string, topN int, topics []string, language string) *OllamaSummarizer {
+	var topic string
+	if len(topics) > 0 {
+		topic = topics[0]
+	}
+
+	return &OllamaSummarizer{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		endpoint:    "chat",
+		model:       model,
+		temperature: -1,
+		topN:        topN,
+		topic:       topic,
+		topics:      topics,
+		language:    language,
+		// Local models on modest hardware can take minutes for a long prompt.
+		client: &http.Client{Timeout: 10 * time.Minute},
+		retryConfig: retry.Config{
+			MaxRetries: 2,
+			BaseDelay:  2 * time.Second,
+		},
+	}
+}
+
+// SetEndpoint selects the Ollama API used: "chat" (/api/chat, the default)
+// or "generate" (/api/generate).
+func (s *OllamaSummarizer) SetEndpoint(endpoint string) {
+	if endpoint != "" {
+		s.endpoint = endpoint
+	}
+}
+
+// SetOptions sets the context window and the sampling temperature. A zero
+// numCtx or a negative temperature keeps the model default.
+func (s *OllamaSummarizer) SetOptions(numCtx int, temperature float64) {
+	s.numCtx = numCtx
+	s.temperature = temperature
+}
+
+// Ollama API request/response types
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages,omitempty"` // chat
+	Prompt   string          `json:"prompt,omitempty"`   // generate
+	Format   string          `json:"format"`
+	Stream   bool            `json:"stream"`
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaOptions struct {
+	NumCtx      int      `json:"num_ctx,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+}
+
+type ollamaResponse struct {
+	Message  ollamaMessage `json:"message"`  // chat
+	Response string        `json:"response"` // generate
+	Error    string        `json:"error,omitempty"`
+
+	PromptEvalCount int `json:"prompt_eval_count"` // Input tokens
+	EvalCount       int `json:"eval_count"`        // Output tokens
+}
+
+func (s *OllamaSummarizer) Summarize(ctx context.Context, papers []fetcher.Paper) (*Digest, error) {
+	if len(papers) == 0 {
+		digest := emptyDigest(s.topic, s.topics, s.language)
+		digest.Backend = s.Backend()
+		return digest, nil
+	}
+
+	if digest, ok := s.cache.digest(papers, s.cacheScope(), s.topN); ok {
+		digest.Topic, digest.Topics = s.topic, s.topics
+		digest.Backend = s.Backend()
+		digest.PromptVersion = s.prompts.Version()
+		digest.Language = s.language
+		return digest, nil
+	}
+	cached := s.cache.lookup(papers, s.cacheScope())
+	prompt, err := s.prompts.digestPrompt(papers, cached, s.topics, s.topN, s.language)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.Complete(ctx, prompt, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := parseDigestLenient(body, papers, s.topic, s.topics)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: %w", err)
+	}
+	digest.Backend = s.Backend()
+	digest.PromptVersion = s.prompts.Version()
+	digest.Language = s.language
+	s.cache.apply(digest, papers, s.cacheScope(), s.topN)
+	return digest, nil
+}
+
+// SetCache makes the summarizer reuse summaries of papers summarized before.
+func (s *OllamaSummarizer) SetCache(cache *Cache) {
+	s.cache = cache
+}
+
+// SetPrompts replaces the built-in digest prompt templates.
+func (s *OllamaSummarizer) SetPrompts(prompts *Prompts) {
+	s.prompts = prompts
+}
+
+func (s *OllamaSummarizer) cacheScope() cacheScope {
+	return cacheScope{backend: s.Backend(), language: s.language, promptVersion: s.prompts.Version()}
+}
+
+// Complete sends prompt and returns the answer. A positive maxTokens bounds
+// the answer through num_predict; zero keeps the model default. It
+// implements Completer.
+func (s *OllamaSummarizer) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	var body string
+	err := retry.WithBackoff(ctx, s.retryConfig, func(ctx context.Context) error {
+		var err error
+		body, err = s.callAPI(ctx, prompt, maxTokens)
+		return err
+	})
+	return body, err
+}
+
+// Backend names the server type and model, e.g. "ollama/llama3.1:8b".
+func (s *OllamaSummarizer) Backend() string {
+	return "ollama/" + s.model
+}
+
+func (s *OllamaSummarizer) callAPI(ctx context.Context, prompt string, numPredict int) (string, error) {
+	reqBody := ollamaRequest{
+		Model:  s.model,
+		Format: "json",
+		Stream: false,
+	}
+	if s.endpoint == "generate" {
+		reqBody.Prompt = prompt
+	} else {
+		reqBody.Messages = []ollamaMessage{{Role: "user", Content: prompt}}
+	}
+	if s.numCtx > 0 || s.temperature >= 0 || numPredict > 0 {
+		reqBody.Options = &ollamaOptions{NumCtx: s.numCtx, NumPredict: numPredict}
+		if s.temperature >= 0 {
+			reqBody.Options.Temperature = &s.temperature
+		}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("ollama: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/api/"+s.endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("ollama: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ollama: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("ollama: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if !retry.HTTPStatusRetryable(resp.StatusCode) {
+			return "", fmt.Errorf("ollama: API error with status %d: %s", resp.StatusCode, string(respBody))
+		}
+		return "", fmt.Errorf("ollama: unexpected status %d", resp.StatusCode)
+	}
+
+	var apiResp ollamaResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return "", fmt.Errorf("ollama: failed to parse response: %w", err)
+	}
+
+	if apiResp.Error != "" {
+		return "", fmt.Errorf("ollama: API error: %s", apiResp.Error)
+	}
+	RecordUsage(ctx, s.Backend(), s.model, apiResp.PromptEvalCount, apiResp.EvalCount)
+
+	text := apiResp.Message.Content
+	if s.endpoint == "generate" {
+		text = apiResp.Response
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("ollama: empty response")
+	}
+	return text, nil
+}
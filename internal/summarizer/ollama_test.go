@@ -0,0 +1,159 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOllamaSummarizeChat(t *testing.T) {
+	var received ollamaRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("Expected path /api/chat, got %q", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		// A typical small-model answer: prose around the object and a trailing comma.
+		content := "Here is the digest:\n{\"overview\": \"Local overview.\", \"summaries\": [{\"index\": \"1\", \"summary\": \"Summary one.\", \"keyPoints\": \"- point A\\n- point B\"},]}"
+		json.NewEncoder(w).Encode(ollamaResponse{Message: ollamaMessage{Role: "assistant", Content: content}})
+	}))
+	defer ts.Close()
+
+	s := NewOllamaSummarizer(ts.URL, "llama3.2", 5, []string{"AI"}, "en")
+	s.SetOptions(8192, 0.2)
+
+	digest, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+
+	if received.Model != "llama3.2" || received.Format != "json" || received.Stream {
+		t.Errorf("Unexpected request: %+v", received)
+	}
+	if len(received.Messages) != 1 || received.Prompt != "" {
+		t.Errorf("Expected the prompt as a chat message, got %+v", received)
+	}
+	if received.Options == nil || received.Options.NumCtx != 8192 || received.Options.Temperature == nil || *received.Options.Temperature != 0.2 {
+		t.Errorf("Expected num_ctx 8192 and temperature 0.2, got %+v", received.Options)
+	}
+
+	if digest.Overview != "Local overview." {
+		t.Errorf("Expected overview 'Local overview.', got %q", digest.Overview)
+	}
+	if len(digest.Summaries) != 1 || digest.Summaries[0].Paper.Title != "Paper One" {
+		t.Fatalf("Expected a summary of Paper One, got %+v", digest.Summaries)
+	}
+	if got := digest.Summaries[0].KeyPoints; len(got) != 2 || got[1] != "point B" {
+		t.Errorf("Expected key points split by line, got %q", got)
+	}
+}
+
+func TestOllamaSummarizeGenerate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("Expected path /api/generate, got %q", r.URL.Path)
+		}
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["prompt"] == nil || req["messages"] != nil {
+			t.Errorf("Expected a prompt instead of messages, got %v", req)
+		}
+		if req["options"] != nil {
+			t.Errorf("Expected no options by default, got %v", req["options"])
+		}
+		w.Write([]byte(`{"response": "{\"overview\": \"ok\", \"summaries\": [{\"index\": 2, \"summary\": \"Two.\", \"key_points\": [\"p\"]}]}", "done": true}`))
+	}))
+	defer ts.Close()
+
+	s := NewOllamaSummarizer(ts.URL+"/", "mistral", 5, []string{"AI"}, "en")
+	s.SetEndpoint("generate")
+
+	digest, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if len(digest.Summaries) != 1 || digest.Summaries[0].Paper.Title != "Paper Two" {
+		t.Errorf("Expected a summary of Paper Two, got %+v", digest.Summaries)
+	}
+}
+
+func TestOllamaSummarizeServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "model \"missing\" not found, try pulling it first"}`))
+	}))
+	defer ts.Close()
+
+	s := NewOllamaSummarizer(ts.URL, "missing", 5, []string{"AI"}, "en")
+	_, err := s.Summarize(context.Background(), samplePapers())
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected model not found error, got: %v", err)
+	}
+}
+
+func TestParseDigestLenient(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantOverview  string
+		wantSummaries int
+	}{
+		{
+			name:          "valid JSON",
+			body:          `{"overview": "o", "summaries": [{"index": 1, "summary": "s", "key_points": ["k"]}]}`,
+			wantOverview:  "o",
+			wantSummaries: 1,
+		},
+		{
+			name:          "reasoning block and fences",
+			body:          "<think>Paper 1 {looks} best.</think>\n```json\n{\"overview\": \"o\", \"summaries\": [{\"index\": 1, \"summary\": \"s\"}]}\n```",
+			wantOverview:  "o",
+			wantSummaries: 1,
+		},
+		{
+			name:          "trailing commas and smart quotes",
+			body:          `{“overview”: “o”, "summaries": [{"index": 2, "summary": "s", "key_points": ["a", "b",],},],}`,
+			wantOverview:  "o",
+			wantSummaries: 1,
+		},
+		{
+			name:          "string index with label",
+			body:          `{"overview": "o", "summaries": [{"paper": "Paper 2", "summary": "s"}]}`,
+			wantOverview:  "o",
+			wantSummaries: 1,
+		},
+		{
+			name:          "cut off mid-summary",
+			body:          `{"overview": "o", "summaries": [{"index": 1, "summary": "complete", "key_points": ["a"]}, {"index": 2, "summary": "cut off here`,
+			wantOverview:  "o",
+			wantSummaries: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest, err := parseDigestLenient(tt.body, samplePapers(), "AI", []string{"AI"})
+			if err != nil {
+				t.Fatalf("parseDigestLenient returned error: %v", err)
+			}
+			if digest.Overview != tt.wantOverview {
+				t.Errorf("Expected overview %q, got %q", tt.wantOverview, digest.Overview)
+			}
+			if len(digest.Summaries) != tt.wantSummaries {
+				t.Errorf("Expected %d summaries, got %d", tt.wantSummaries, len(digest.Summaries))
+			}
+		})
+	}
+}
+
+func TestParseDigestLenientRejectsProse(t *testing.T) {
+	_, err := parseDigestLenient("I could not find any relevant papers.", samplePapers(), "AI", []string{"AI"})
+	if err == nil || !strings.Contains(err.Error(), "no JSON object found") {
+		t.Errorf("Expected no JSON object error, got: %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/retry"
+)
+
+// OpenAISummarizer summarizes papers through an OpenAI-compatible
+// /chat/completions endpoint, such as a vLLM or LiteLLM gateway. It sends the
+// same prompt as AnthropicSummarizer and produces digests of the same shape.
+type OpenAISummarizer struct {
+	baseURL     string // e.g. "https://api.openai.com/v1"
+	apiKey      string // Sent as a bearer token when set
+	model       string
+	maxTokens   int
+	topN        int
+	topic       string   // First topic, kept on the digest for backward compatibility
+	topics      []string // Multiple topics
+	language    string
+	headers     map[string]string // Extra request headers, e.g. for gateway routing
+	jsonMode    bool              // Request response_format json_object
+	client      *http.Client
+	retryConfig retry.Config
+	cache       *Cache   // Optional per-paper summary cache
+	prompts     *Prompts // Digest prompt templates; nil uses the built-in ones
+}
+
+func NewOpenAISummarizer(baseURL, apiKey, model string, maxTokens, topN int, topics []string, language string) *OpenAISummarizer {
+	var topic string
+	if len(topics) > 0 {
+		topic = topics[0]
+	}
+
+	return &OpenAISummarizer{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		apiKey:    apiKey,
+		model:     model,
+		maxTokens: maxTokens,
+		topN:      topN,
+		topic:     topic,
+		topics:    topics,
+		language:  language,
+		client:    &http.Client{Timeout: 120 * time.Second},
+		retryConfig: retry.Config{
+			MaxRetries: 3,
+			BaseDelay:  2 * time.Second,
+		},
+	}
+}
+
+// SetHeaders sets extra headers sent with every request.
+func (s *OpenAISummarizer) SetHeaders(headers map[string]string) {
+	s.headers = headers
+}
+
+// SetJSONMode makes requests ask for a JSON object response. Not every
+// compatible server supports response_format, so it is off by default.
+func (s *OpenAISummarizer) SetJSONMode(enabled bool) {
+	s.jsonMode = enabled
+}
+
+// OpenAI chat completions request/response types
+
+type openaiRequest struct {
+	Model          string                `json:"model"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	Messages       []openaiMessage       `json:"messages"`
+	ResponseFormat *openaiResponseFormat `json:"response_format,omitempty"`
+}
+
+type openaiMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openaiResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type openaiResponse struct {
+	Choices []openaiChoice `json:"choices"`
+	Usage   openaiUsage    `json:"usage"`
+	Error   *openaiError   `json:"error,omitempty"`
+}
+
+type openaiUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openaiChoice struct {
+	Message      openaiMessage `json:"message"`
+	FinishReason string        `json:"finish_reason"`
+}
+
+type openaiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func (s *OpenAISummarizer) Summarize(ctx context.Context, papers []fetcher.Paper) (*Digest, error) {
+	if len(papers) == 0 {
+		digest := emptyDigest(s.topic, s.topics, s.language)
+		digest.Backend = s.Backend()
+		return digest, nil
+	}
+
+	if digest, ok := s.cache.digest(papers, s.cacheScope(), s.topN); ok {
+		digest.Topic, digest.Topics = s.topic, s.topics
+		digest.Backend = s.Backend()
+		digest.PromptVersion = s.prompts.Version()
+		digest.Language = s.language
+		return digest, nil
+	}
+	cached := s.cache.lookup(papers, s.cacheScope())
+	prompt, err := s.prompts.digestPrompt(papers, cached, s.topics, s.topN, s.language)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.Complete(ctx, prompt, s.maxTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := parseDigest(body, papers, s.topic, s.topics)
+	if err != nil {
+		return nil, fmt.Errorf("openai: %w", err)
+	}
+	digest.Backend = s.Backend()
+	digest.PromptVersion = s.prompts.Version()
+	digest.Language = s.language
+	s.cache.apply(digest, papers, s.cacheScope(), s.topN)
+	return digest, nil
+}
+
+// SetCache makes the summarizer reuse summaries of papers summarized before.
+func (s *OpenAISummarizer) SetCache(cache *Cache) {
+	s.cache = cache
+}
+
+// SetPrompts replaces the built-in digest prompt templates.
+func (s *OpenAISummarizer) SetPrompts(prompts *Prompts) {
+	s.prompts = prompts
+}
+
+func (s *OpenAISummarizer) cacheScope() cacheScope {
+	return cacheScope{backend: s.Backend(), language: s.language, promptVersion: s.prompts.Version()}
+}
+
+// Complete sends prompt as a single user message and returns the answer,
+// bounded by maxTokens. It implements Completer.
+func (s *OpenAISummarizer) Complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	var body string
+	err := retry.WithBackoff(ctx, s.retryConfig, func(ctx context.Context) error {
+		var err error
+		body, err = s.callAPI(ctx, prompt, maxTokens)
+		return err
+	})
+	return body, err
+}
+
+// Backend names the endpoint type and model, e.g. "openai/gpt-4o-mini".
+func (s *OpenAISummarizer) Backend() string {
+	return "openai/" + s.model
+}
+
+func (s *OpenAISummarizer) callAPI(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	reqBody := openaiRequest{
+		Model:     s.model,
+		MaxTokens: maxTokens,
+		Messages: []openaiMessage{
+			{Role: "user", Content: prompt},
+		},
+	}
+	if s.jsonMode {
+		reqBody.ResponseFormat = &openaiResponseFormat{Type: "json_object"}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("openai: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/chat/completions", bytes.NewReader(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("openai: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("openai: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("openai: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if !retry.HTTPStatusRetryable(resp.StatusCode) {
+			return "", fmt.Errorf("openai: API error with status %d: %s", resp.StatusCode, string(respBody))
+		}
+		return "", fmt.Errorf("openai: unexpected status %d", resp.StatusCode)
+	}
+
+	var apiResp openaiResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return "", fmt.Errorf("openai: failed to parse response: %w", err)
+	}
+
+	if apiResp.Error != nil {
+		return "", fmt.Errorf("openai: API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
+	}
+	RecordUsage(ctx, s.Backend(), s.model, apiResp.Usage.PromptTokens, apiResp.Usage.CompletionTokens)
+
+	if len(apiResp.Choices) == 0 || apiResp.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("openai: empty response")
+	}
+
+	return apiResp.Choices[0].Message.Content, nil
+}
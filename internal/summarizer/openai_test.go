@@ -0,0 +1,162 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAISummarizeWithMockAPI(t *testing.T) {
+	responseJSON := digestJSON{
+		Overview: "Gateway overview.",
+		Summaries: []summaryJSON{
+			{Index: 2, Summary: "Summary of paper two.", KeyPoints: []string{"point B"}},
+		},
+	}
+
+	var received openaiRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Expected path /v1/chat/completions, got %q", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Team") != "research" {
+			t.Errorf("Expected custom header X-Team, got %q", r.Header.Get("X-Team"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		json.NewEncoder(w).Encode(openaiResponse{
+			Choices: []openaiChoice{
+				{Message: openaiMessage{Role: "assistant", Content: mustMarshal(t, responseJSON)}, FinishReason: "stop"},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	s := NewOpenAISummarizer(ts.URL+"/v1/", "test-key", "llama-3-70b", 1024, 5, []string{"AI", "ML"}, "en")
+	s.SetHeaders(map[string]string{"X-Team": "research"})
+	s.SetJSONMode(true)
+
+	digest, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+
+	if received.Model != "llama-3-70b" || received.MaxTokens != 1024 {
+		t.Errorf("Unexpected model or max_tokens: %+v", received)
+	}
+	if received.ResponseFormat == nil || received.ResponseFormat.Type != "json_object" {
+		t.Errorf("Expected json_object response format, got %+v", received.ResponseFormat)
+	}
+	want, err := defaultPrompts.digestPrompt(samplePapers(), nil, []string{"AI", "ML"}, 5, "en")
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+	if len(received.Messages) != 1 || received.Messages[0].Content != want {
+		t.Error("Expected the shared digest prompt as the only message")
+	}
+
+	if digest.Overview != "Gateway overview." {
+		t.Errorf("Expected overview 'Gateway overview.', got %q", digest.Overview)
+	}
+	if len(digest.Summaries) != 1 || digest.Summaries[0].Paper.Title != "Paper Two" {
+		t.Fatalf("Expected a summary of Paper Two, got %+v", digest.Summaries)
+	}
+	if digest.Topic != "AI" || len(digest.Topics) != 2 {
+		t.Errorf("Expected topics to be recorded, got %q and %v", digest.Topic, digest.Topics)
+	}
+}
+
+func TestOpenAISummarizeOmitsOptionalFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Expected no Authorization header without an API key, got %q", r.Header.Get("Authorization"))
+		}
+		var raw map[string]any
+		json.NewDecoder(r.Body).Decode(&raw)
+		if _, ok := raw["response_format"]; ok {
+			t.Error("Expected no response_format without JSON mode")
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"overview\": \"ok\", \"summaries\": []}"}}]}`))
+	}))
+	defer ts.Close()
+
+	s := NewOpenAISummarizer(ts.URL, "", "local-model", 1024, 5, []string{"AI"}, "en")
+	if _, err := s.Summarize(context.Background(), samplePapers()); err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+}
+
+func TestOpenAISummarizeAPIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"type": "invalid_request_error", "message": "unknown model"}}`))
+	}))
+	defer ts.Close()
+
+	s := NewOpenAISummarizer(ts.URL, "test-key", "missing", 1024, 5, []string{"AI"}, "en")
+	_, err := s.Summarize(context.Background(), samplePapers())
+	if err == nil {
+		t.Fatal("Expected error for API error response")
+	}
+	if !strings.Contains(err.Error(), "API error with status 400") {
+		t.Errorf("Expected 'API error with status 400' in error message, got: %v", err)
+	}
+}
+
+func TestOpenAISummarizeReusesCachedSummaries(t *testing.T) {
+	var prompts []string
+	responses := []string{
+		`{"overview": "First run.", "summaries": [{"index": 1, "summary": "Summary of paper two.", "key_points": ["point B"]}]}`,
+		`{"overview": "Second run.", "summaries": [{"index": 2}, {"index": 1, "summary": "Summary of paper one.", "key_points": ["point A"]}]}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openaiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		prompts = append(prompts, req.Messages[0].Content)
+		json.NewEncoder(w).Encode(openaiResponse{
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: responses[len(prompts)-1]}}},
+		})
+	}))
+	defer ts.Close()
+
+	cache, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCache returned error: %v", err)
+	}
+	s := NewOpenAISummarizer(ts.URL, "", "local-model", 1024, 5, []string{"AI"}, "en")
+	s.SetCache(cache)
+
+	if _, err := s.Summarize(context.Background(), samplePapers()[1:]); err != nil {
+		t.Fatalf("First Summarize returned error: %v", err)
+	}
+	digest, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Second Summarize returned error: %v", err)
+	}
+
+	if !strings.Contains(prompts[1], "Summary (already written): Summary of paper two.") || strings.Contains(prompts[1], "Abstract two") {
+		t.Error("Expected the cached summary instead of the abstract in the second prompt")
+	}
+	if !strings.Contains(prompts[1], "Abstract one") {
+		t.Error("Expected the uncached paper's abstract in the second prompt")
+	}
+	if len(digest.Summaries) != 2 || digest.Summaries[0].Summary != "Summary of paper two." || digest.Summaries[0].KeyPoints[0] != "point B" {
+		t.Errorf("Expected the cached summary of paper two to be reused, got %+v", digest.Summaries)
+	}
+
+	// Once every selected paper is cached, the same papers need no request.
+	again, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Third Summarize returned error: %v", err)
+	}
+	if len(prompts) != 2 || again.Overview != "Second run." || len(again.Summaries) != 2 || again.Summaries[1].Summary != "Summary of paper one." {
+		t.Errorf("Expected the cached digest without a request, got %d requests and %+v", len(prompts), again)
+	}
+}
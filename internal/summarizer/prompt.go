@@ -0,0 +1,79 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/i18n"
+)
+
+// Prompt and response handling shared by the LLM-backed summarizers.
+
+// digestJSON is the expected JSON structure from the LLM.
+type digestJSON struct {
+	Overview  string        `json:"overview"`
+	Summaries []summaryJSON `json:"summaries"`
+}
+
+type summaryJSON struct {
+	Index     int      `json:"index"`
+	Summary   string   `json:"summary"`
+	KeyPoints []string `json:"key_points"`
+}
+
+// emptyDigest returns the digest of a run without papers.
+func emptyDigest(topic string, topics []string, language string) *Digest {
+	return &Digest{
+		Topic:    topic, // For backward compatibility
+		Topics:   topics,
+		Date:     time.Now(),
+		Overview: i18n.Get(language).T("no_papers", strings.Join(topics, ", ")),
+		Language: language,
+	}
+}
+
+// parseDigest parses an LLM response in the digestJSON shape, with or without
+// markdown fences, into a digest of papers. Summaries with an out-of-range
+// index are dropped.
+func parseDigest(body string, papers []fetcher.Paper, topic string, topics []string) (*Digest, error) {
+	// Strip markdown fences if present
+	body = strings.TrimSpace(body)
+	body = strings.TrimPrefix(body, "```json")
+	body = strings.TrimPrefix(body, "```")
+	body = strings.TrimSuffix(body, "```")
+	body = strings.TrimSpace(body)
+
+	var dj digestJSON
+	if err := json.Unmarshal([]byte(body), &dj); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM JSON: %w\nraw response: %s", err, body)
+	}
+
+	return newDigest(dj, papers, topic, topics), nil
+}
+
+// newDigest builds the digest described by dj.
+func newDigest(dj digestJSON, papers []fetcher.Paper, topic string, topics []string) *Digest {
+	digest := &Digest{
+		Topic:    topic, // For backward compatibility
+		Topics:   topics,
+		Date:     time.Now(),
+		Overview: dj.Overview,
+	}
+
+	for _, sj := range dj.Summaries {
+		idx := sj.Index - 1 // Convert from 1-based to 0-based
+		if idx < 0 || idx >= len(papers) {
+			continue
+		}
+		digest.Summaries = append(digest.Summaries, PaperSummary{
+			Paper:     papers[idx],
+			Summary:   sj.Summary,
+			KeyPoints: sj.KeyPoints,
+		})
+	}
+
+	return digest
+}
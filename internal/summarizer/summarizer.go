@@ -10,18 +10,23 @@ import (
 
 // PaperSummary holds a paper and its generated summary.
 type PaperSummary struct {
-	Paper     fetcher.Paper
-	Summary   string
-	KeyPoints []string
+	Paper     fetcher.Paper `json:"paper"`
+	Summary   string        `json:"summary"`
+	KeyPoints []string      `json:"key_points"`
 }
 
 // Digest is the final output of the summarization pipeline.
 type Digest struct {
-	Topic     string    // Legacy single topic for backward compatibility
-	Topics    []string  // Multiple topics
-	Date      time.Time
-	Summaries []PaperSummary
-	Overview  string // High-level overview of all papers
+	Topic     string         `json:"topic"`  // Legacy single topic for backward compatibility
+	Topics    []string       `json:"topics"` // Multiple topics
+	Date      time.Time      `json:"date"`
+	Summaries []PaperSummary `json:"summaries"`
+	Overview  string         `json:"overview"`          // High-level overview of all papers
+	Backend   string         `json:"backend,omitempty"` // Summarizer that produced the digest, e.g. "anthropic/claude-sonnet-4-20250514"
+	Usage     []Usage        `json:"usage,omitempty"`   // Token usage of the run, if shown
+
+	PromptVersion string `json:"prompt_version,omitempty"` // Prompts the digest was produced with
+	Language      string `json:"language,omitempty"`       // Language of the text; publishers localize their headings to it
 }
 
 // GetTopicsString returns a comma-separated string of all topics for display purposes.
@@ -35,4 +40,4 @@ func (d *Digest) GetTopicsString() string {
 // Summarizer takes a list of papers and produces a digest with summaries.
 type Summarizer interface {
 	Summarize(ctx context.Context, papers []fetcher.Paper) (*Digest, error)
-}
\ No newline at end of file
+}
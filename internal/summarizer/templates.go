@@ -0,0 +1,219 @@
+package summarizer
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/ryosukesatoh/daily-feed/internal/fetcher"
+	"github.com/ryosukesatoh/daily-feed/internal/i18n"
+)
+
+//go:embed prompts/*.tmpl
+var builtinTemplates embed.FS
+
+// builtinPromptVersion identifies the built-in prompts, including those of
+// the map-reduce mode. Bump it whenever they change in a way that affects
+// summaries, so that cached summaries are not reused.
+const builtinPromptVersion = "builtin-2"
+
+// Prompt template kinds, which name the template files
+// <kind>.<language>.tmpl.
+const (
+	digestPromptKind = "digest"           // Single-call digest of the LLM backends
+	mapPromptKind    = "mapreduce_map"    // Map phase of the map_reduce mode
+	reducePromptKind = "mapreduce_reduce" // Reduce phase of the map_reduce mode
+)
+
+var promptKinds = []string{digestPromptKind, mapPromptKind, reducePromptKind}
+
+// promptFuncs are the functions available to prompt templates.
+var promptFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// promptData is what a prompt template receives. The digest and map
+// templates list Papers, the reduce template Candidates.
+type promptData struct {
+	Papers       []promptPaper
+	Candidates   []promptCandidate
+	Total        int // Papers in the whole pool, for the map-reduce prompts
+	Topics       []string
+	TopN         int
+	Language     string
+	LanguageName string // English name of the language when the template is not written in it
+	MultiTopic   bool   // More than one topic
+	Cached       bool   // Some papers have a summary written in an earlier run
+}
+
+// T returns a message of the catalog of the prompt language, such as the
+// "prompt_title" label, so that templates shared by several languages label
+// paper fields in the language of the answer.
+func (d promptData) T(key string, args ...any) string {
+	return i18n.Get(d.Language).T(key, args...)
+}
+
+// promptPaper is a paper as seen by a prompt template.
+type promptPaper struct {
+	fetcher.Paper
+	Number  int    // 1-based number the model answers with
+	Summary string // Cached summary, shown instead of the abstract
+}
+
+// promptCandidate is a candidate of the map phase as seen by the reduce
+// template.
+type promptCandidate struct {
+	fetcher.Paper
+	Number  int // 1-based number the model answers with
+	Score   int
+	Summary string
+}
+
+// Prompts renders the prompts of the LLM-backed summarizers from
+// text/template templates named <kind>.<language>.tmpl, falling back to the
+// English one, which asks for an answer in the language, for languages
+// without a template. A nil *Prompts uses the built-in templates.
+type Prompts struct {
+	version   string
+	templates map[string]*template.Template // By kind and language, e.g. "digest.en"
+}
+
+var defaultPrompts = mustParseBuiltinPrompts()
+
+func mustParseBuiltinPrompts() *Prompts {
+	p := &Prompts{version: builtinPromptVersion, templates: make(map[string]*template.Template)}
+	if _, err := p.parse(builtinTemplates, "prompts"); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// LoadPrompts returns the built-in templates, overridden by the
+// <kind>.<language>.tmpl files found in dir. The version identifies the
+// prompts in digests, logs and the summary cache; when empty, one is derived
+// from the contents of the override files, so editing them invalidates
+// cached summaries.
+func LoadPrompts(dir, version string) (*Prompts, error) {
+	p := &Prompts{version: version, templates: make(map[string]*template.Template)}
+	for name, t := range defaultPrompts.templates {
+		p.templates[name] = t
+	}
+	hash, err := p.parse(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		return nil, fmt.Errorf("summarizer: no prompt templates in %s (expected digest, mapreduce_map or mapreduce_reduce.<language>.tmpl)", dir)
+	}
+	if p.version == "" {
+		p.version = "custom-" + hash[:12]
+	}
+	return p, nil
+}
+
+// parse parses the templates of every kind in dir of fsys and checks that
+// they render. It returns a hash of the templates, or "" when there are none.
+func (p *Prompts) parse(fsys fs.FS, dir string) (string, error) {
+	var names []string
+	for _, kind := range promptKinds {
+		matches, err := fs.Glob(fsys, path.Join(dir, kind+".*.tmpl"))
+		if err != nil {
+			return "", err
+		}
+		names = append(names, matches...)
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	h := sha256.New()
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return "", fmt.Errorf("summarizer: failed to read prompt template: %w", err)
+		}
+		base := path.Base(name)
+		t, err := template.New(base).Funcs(promptFuncs).Parse(string(data))
+		if err != nil {
+			return "", fmt.Errorf("summarizer: invalid prompt template %s: %w", base, err)
+		}
+		if err := t.Execute(new(strings.Builder), samplePromptData()); err != nil {
+			return "", fmt.Errorf("summarizer: prompt template %s does not render: %w", base, err)
+		}
+		p.templates[strings.TrimSuffix(base, ".tmpl")] = t
+		fmt.Fprintf(h, "%s\x00%s\x00", base, data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// samplePromptData exercises every branch of a template when checking it.
+func samplePromptData() promptData {
+	return promptData{
+		Papers: []promptPaper{
+			{Paper: fetcher.Paper{Title: "T", Authors: []string{"A"}, Abstract: "A.", Topics: []string{"a", "b"}, Comment: "C", JournalRef: "J", Excerpt: "E"}, Number: 1},
+			{Paper: fetcher.Paper{Title: "T", Authors: []string{"A"}, Abstract: "A."}, Number: 2, Summary: "S."},
+		},
+		Candidates: []promptCandidate{
+			{Paper: fetcher.Paper{Title: "T", Category: "C", Topics: []string{"a"}}, Number: 1, Score: 7, Summary: "S."},
+		},
+		Total:        3,
+		Topics:       []string{"a", "b"},
+		TopN:         1,
+		Language:     "en",
+		LanguageName: "English",
+		MultiTopic:   true,
+		Cached:       true,
+	}
+}
+
+// Version identifies the prompts.
+func (p *Prompts) Version() string {
+	return p.orDefault().version
+}
+
+func (p *Prompts) orDefault() *Prompts {
+	if p == nil {
+		return defaultPrompts
+	}
+	return p
+}
+
+// digestPrompt asks the model to rank papers by relevance to topics, select
+// the topN most important ones and answer with JSON in the digestJSON shape.
+// Papers with a cached summary, by index, are shown with that summary instead
+// of their abstract, and the model is asked not to summarize them again.
+func (p *Prompts) digestPrompt(papers []fetcher.Paper, cached map[int]cachedSummary, topics []string, topN int, language string) (string, error) {
+	data := promptData{
+		Topics:     topics,
+		TopN:       topN,
+		MultiTopic: len(topics) > 1,
+		Cached:     len(cached) > 0,
+	}
+	for i, paper := range papers {
+		data.Papers = append(data.Papers, promptPaper{Paper: paper, Number: i + 1, Summary: cached[i].Summary})
+	}
+	return p.render(digestPromptKind, language, data)
+}
+
+// render executes the template of kind for language with data.
+func (p *Prompts) render(kind, language string, data promptData) (string, error) {
+	p = p.orDefault()
+	data.Language = language
+	t, ok := p.templates[kind+"."+language]
+	if !ok {
+		t = p.templates[kind+"."+i18n.Fallback]
+		data.LanguageName = i18n.Get(language).Language()
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("summarizer: failed to render prompt template %s: %w", t.Name(), err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
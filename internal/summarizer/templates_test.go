@@ -0,0 +1,213 @@
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const customTemplate = `Pick {{.TopN}} of these papers on {{join .Topics " and "}} ({{.Language}}):
+{{range .Papers}}[{{.Number}}] {{.Title}}
+{{end}}`
+
+func TestLoadPromptsOverridesTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "digest.en.tmpl", customTemplate)
+
+	p, err := LoadPrompts(dir, "")
+	if err != nil {
+		t.Fatalf("LoadPrompts returned error: %v", err)
+	}
+	if !strings.HasPrefix(p.Version(), "custom-") {
+		t.Errorf("Expected a derived custom version, got %q", p.Version())
+	}
+
+	prompt, err := p.digestPrompt(samplePapers(), nil, []string{"AI", "ML"}, 3, "en")
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+	if want := "Pick 3 of these papers on AI and ML (en):\n[1] Paper One\n[2] Paper Two"; prompt != want {
+		t.Errorf("Expected the custom prompt %q, got %q", want, prompt)
+	}
+
+	// Japanese has no override and keeps the built-in template.
+	prompt, err = p.digestPrompt(samplePapers(), nil, []string{"AI"}, 3, "ja")
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+	if builtin, _ := defaultPrompts.digestPrompt(samplePapers(), nil, []string{"AI"}, 3, "ja"); prompt != builtin {
+		t.Error("Expected the built-in Japanese template")
+	}
+
+	// Editing a template changes the derived version; an explicit one is kept.
+	writeTemplate(t, dir, "digest.en.tmpl", customTemplate+"Answer in JSON.")
+	edited, err := LoadPrompts(dir, "")
+	if err != nil {
+		t.Fatalf("LoadPrompts returned error: %v", err)
+	}
+	if edited.Version() == p.Version() {
+		t.Error("Expected a new version after editing the template")
+	}
+	if named, err := LoadPrompts(dir, "tone-v2"); err != nil || named.Version() != "tone-v2" {
+		t.Errorf("Expected version tone-v2, got %v, %v", named, err)
+	}
+}
+
+func TestLoadPromptsRejectsBrokenTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{"syntax", "{{range .Papers}}", "invalid prompt template digest.en.tmpl"},
+		{"unknown field", "{{.Abstracts}}", "does not render"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTemplate(t, dir, "digest.en.tmpl", tt.template)
+			if _, err := LoadPrompts(dir, ""); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := LoadPrompts(t.TempDir(), ""); err == nil || !strings.Contains(err.Error(), "no prompt templates in") {
+		t.Errorf("Expected an error for a directory without templates, got: %v", err)
+	}
+}
+
+func TestSummarizeRecordsPromptVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "digest.en.tmpl", customTemplate)
+	prompts, err := LoadPrompts(dir, "tone-v2")
+	if err != nil {
+		t.Fatalf("LoadPrompts returned error: %v", err)
+	}
+
+	var received openaiRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(openaiResponse{
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: `{"overview": "o", "summaries": []}`}}},
+		})
+	}))
+	defer ts.Close()
+
+	s := NewOpenAISummarizer(ts.URL, "", "local-model", 1024, 5, []string{"AI"}, "en")
+	digest, err := s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if digest.PromptVersion != builtinPromptVersion {
+		t.Errorf("Expected the built-in prompt version, got %q", digest.PromptVersion)
+	}
+
+	s.SetPrompts(prompts)
+	digest, err = s.Summarize(context.Background(), samplePapers())
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if !strings.HasPrefix(received.Messages[0].Content, "Pick 5 of these papers on AI") {
+		t.Errorf("Expected the custom prompt to be sent, got %q", received.Messages[0].Content)
+	}
+	if digest.PromptVersion != "tone-v2" {
+		t.Errorf("Expected prompt version tone-v2, got %q", digest.PromptVersion)
+	}
+}
+
+func TestDigestPromptAsksForLanguagesWithoutTemplate(t *testing.T) {
+	en, err := defaultPrompts.digestPrompt(samplePapers(), nil, []string{"AI"}, 3, "en")
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+	if strings.Contains(en, "Write the overview") {
+		t.Error("Expected no language instruction in the English prompt")
+	}
+
+	de, err := defaultPrompts.digestPrompt(samplePapers(), nil, []string{"AI"}, 3, "de")
+	if err != nil {
+		t.Fatalf("digestPrompt returned error: %v", err)
+	}
+	if !strings.HasSuffix(de, "\n\nWrite the overview, summaries and key points in German.") {
+		t.Errorf("Expected the English prompt with a language instruction, got %q", de)
+	}
+	// Paper fields are labeled from the German catalog.
+	for _, want := range []string{"Titel: Paper One\n", "Autoren: Alice, Bob\n", "Kategorie: cs.AI\n"} {
+		if !strings.Contains(de, want) {
+			t.Errorf("Expected %q in the German prompt", want)
+		}
+	}
+	if strings.Contains(de, "Title:") || strings.Contains(de, "Authors:") {
+		t.Error("Expected no English field labels in the German prompt")
+	}
+}
+
+func TestMapReducePromptsLabelFieldsInLanguage(t *testing.T) {
+	zh := NewMapReduceSummarizer(&fakeCompleter{}, 2, []string{"AI"}, "zh")
+	p, err := zh.mapPrompt(numberedPapers(1), 1)
+	if err != nil {
+		t.Fatalf("mapPrompt returned error: %v", err)
+	}
+	for _, want := range []string{"标题: P1\n", "分类: cs.AI\n", "论文摘要: Abstract.\n"} {
+		if !strings.Contains(p, want) {
+			t.Errorf("Expected %q in the Chinese map prompt, got %q", want, p)
+		}
+	}
+
+	shortlist := []candidate{{paper: numberedPapers(1)[0], score: 5, summary: "s"}}
+	p, err = zh.reducePrompt(shortlist, 1)
+	if err != nil {
+		t.Fatalf("reducePrompt returned error: %v", err)
+	}
+	if !strings.Contains(p, "评分: 5\n摘要: s\n") {
+		t.Errorf("Expected Chinese candidate labels in the reduce prompt, got %q", p)
+	}
+}
+
+func TestMapReduceUsesLoadedPrompts(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "mapreduce_map.en.tmpl", "Score these {{len .Papers}} of {{.Total}}:\n{{range .Papers}}Title: {{.Title}}\n{{end}}")
+	writeTemplate(t, dir, "mapreduce_reduce.en.tmpl", "Pick {{.TopN}}:{{range .Candidates}} [{{.Number}}] {{.Title}} ({{.Score}}){{end}}")
+	prompts, err := LoadPrompts(dir, "mr-v2")
+	if err != nil {
+		t.Fatalf("LoadPrompts returned error: %v", err)
+	}
+
+	var reducePrompt string
+	fc := &fakeCompleter{respond: func(prompt string, maxTokens int) (string, error) {
+		if strings.HasPrefix(prompt, "Pick") {
+			reducePrompt = prompt
+			return `{"overview": "o", "selected": [1]}`, nil
+		}
+		if !strings.HasPrefix(prompt, "Score these 3 of 3:") {
+			t.Errorf("Expected the custom map prompt, got %q", prompt)
+		}
+		return scoreByTitle(prompt), nil
+	}}
+	s := NewMapReduceSummarizer(fc, 1, []string{"AI"}, "en")
+	s.SetPrompts(prompts)
+
+	digest, err := s.Summarize(context.Background(), numberedPapers(3))
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	if want := "Pick 1: [1] P3 (3) [2] P2 (2) [3] P1 (1)"; reducePrompt != want {
+		t.Errorf("Expected the custom reduce prompt %q, got %q", want, reducePrompt)
+	}
+	if digest.PromptVersion != "mr-v2" || s.cacheScope().promptVersion != "mr-v2" {
+		t.Errorf("Expected prompt version mr-v2 on the digest and cache scope, got %q and %q", digest.PromptVersion, s.cacheScope().promptVersion)
+	}
+}
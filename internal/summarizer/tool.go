@@ -0,0 +1,111 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Structured output through tool use: instead of asking for JSON in free
+// text, the model is made to call a submit_digest tool whose input schema
+// has the digestJSON shape.
+
+const (
+	digestToolName        = "submit_digest"
+	digestToolDescription = "Submit the digest: an overview of the papers and a summary of each selected paper. Call this exactly once."
+)
+
+// digestToolSchema is the JSON schema of the submit_digest tool input.
+var digestToolSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "overview": {
+      "type": "string",
+      "description": "A 2-3 sentence overview of the most important trends and findings"
+    },
+    "summaries": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "index": {"type": "integer", "minimum": 1, "description": "1-based paper number from the list"},
+          "summary": {"type": "string", "description": "2-3 sentence summary of the paper; omit for papers with a summary already written"},
+          "key_points": {"type": "array", "items": {"type": "string"}, "description": "3-5 key points; omit for papers with a summary already written"}
+        },
+        "required": ["index"]
+      }
+    }
+  },
+  "required": ["overview", "summaries"]
+}`)
+
+// toolDigest mirrors digestJSON with pointers, so that missing fields can be
+// told apart from empty ones.
+type toolDigest struct {
+	Overview  *string        `json:"overview"`
+	Summaries *[]toolSummary `json:"summaries"`
+}
+
+type toolSummary struct {
+	Index     *int      `json:"index"`
+	Summary   *string   `json:"summary"`
+	KeyPoints *[]string `json:"key_points"`
+}
+
+// validateDigestInput checks a submit_digest input against digestToolSchema
+// and the papers of the prompt. Summaries are required except for papers with
+// a cached summary, by 0-based index. It returns the decoded digest and the
+// problems found, which are empty when the input is valid.
+func validateDigestInput(input json.RawMessage, numPapers int, cached map[int]cachedSummary) (digestJSON, []string) {
+	var td toolDigest
+	if err := json.Unmarshal(input, &td); err != nil {
+		return digestJSON{}, []string{fmt.Sprintf("input does not match the schema: %v", err)}
+	}
+
+	var dj digestJSON
+	var problems []string
+	if td.Overview == nil || *td.Overview == "" {
+		problems = append(problems, "overview is required")
+	} else {
+		dj.Overview = *td.Overview
+	}
+	if td.Summaries == nil {
+		problems = append(problems, "summaries is required")
+		return dj, problems
+	}
+
+	seen := make(map[int]bool)
+	for i, ts := range *td.Summaries {
+		field := fmt.Sprintf("summaries[%d]", i)
+		switch {
+		case ts.Index == nil:
+			problems = append(problems, field+".index is required")
+		case *ts.Index < 1 || *ts.Index > numPapers:
+			problems = append(problems, fmt.Sprintf("%s.index %d is out of range (papers are numbered 1 to %d)", field, *ts.Index, numPapers))
+		case seen[*ts.Index]:
+			problems = append(problems, fmt.Sprintf("%s.index %d is a duplicate", field, *ts.Index))
+		default:
+			seen[*ts.Index] = true
+		}
+		isCached := false
+		if ts.Index != nil {
+			_, isCached = cached[*ts.Index-1]
+		}
+		if !isCached && (ts.Summary == nil || *ts.Summary == "") {
+			problems = append(problems, field+".summary is required")
+		}
+		if !isCached && ts.KeyPoints == nil {
+			problems = append(problems, field+".key_points is required")
+		}
+		if len(problems) == 0 {
+			sj := summaryJSON{Index: *ts.Index}
+			if ts.Summary != nil {
+				sj.Summary = *ts.Summary
+			}
+			if ts.KeyPoints != nil {
+				sj.KeyPoints = *ts.KeyPoints
+			}
+			dj.Summaries = append(dj.Summaries, sj)
+		}
+	}
+	return dj, problems
+}
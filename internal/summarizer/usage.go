@@ -0,0 +1,103 @@
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Price is the cost of a model in USD per million tokens.
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// Usage is the token usage of one summarizer backend during a run.
+type Usage struct {
+	Backend      string  `json:"backend"` // e.g. "anthropic/claude-sonnet-4-20250514"
+	Calls        int     `json:"calls"`   // API calls that returned a response
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"` // 0 for backends without a configured price
+}
+
+// String formats the usage for logs and footers.
+func (u Usage) String() string {
+	return fmt.Sprintf("%s: %d calls, %d input + %d output tokens, $%.4f", u.Backend, u.Calls, u.InputTokens, u.OutputTokens, u.CostUSD)
+}
+
+// TotalCost adds up the cost of all backends.
+func TotalCost(usage []Usage) float64 {
+	var total float64
+	for _, u := range usage {
+		total += u.CostUSD
+	}
+	return total
+}
+
+// Meter adds up the token usage of the API calls made during a run, per
+// backend. The LLM-backed summarizers report to the meter attached to the
+// context of Summarize with WithMeter; without one, usage is not recorded.
+type Meter struct {
+	prices map[string]Price // By backend or model name
+
+	mu       sync.Mutex
+	usage    []Usage // In order of first use
+	unpriced map[string]bool
+}
+
+// NewMeter returns a meter pricing tokens with prices, keyed by backend
+// (e.g. "openai/gpt-4o") or by model name alone.
+func NewMeter(prices map[string]Price) *Meter {
+	return &Meter{prices: prices, unpriced: make(map[string]bool)}
+}
+
+type meterKey struct{}
+
+// WithMeter returns a context whose API calls are recorded by m.
+func WithMeter(ctx context.Context, m *Meter) context.Context {
+	return context.WithValue(ctx, meterKey{}, m)
+}
+
+// RecordUsage records one API call with the meter of ctx, if any. Summarizers
+// call it for every response they receive.
+func RecordUsage(ctx context.Context, backend, model string, inputTokens, outputTokens int) {
+	if m, ok := ctx.Value(meterKey{}).(*Meter); ok {
+		m.add(backend, model, inputTokens, outputTokens)
+	}
+}
+
+func (m *Meter) add(backend, model string, inputTokens, outputTokens int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	price, ok := m.prices[backend]
+	if !ok {
+		price, ok = m.prices[model]
+	}
+	if !ok && len(m.prices) > 0 && !m.unpriced[backend] {
+		m.unpriced[backend] = true
+		log.Printf("WARNING: no price configured for %s, its cost is counted as 0", backend)
+	}
+
+	i := 0
+	for i < len(m.usage) && m.usage[i].Backend != backend {
+		i++
+	}
+	if i == len(m.usage) {
+		m.usage = append(m.usage, Usage{Backend: backend})
+	}
+	u := &m.usage[i]
+	u.Calls++
+	u.InputTokens += inputTokens
+	u.OutputTokens += outputTokens
+	u.CostUSD = (float64(u.InputTokens)*price.Input + float64(u.OutputTokens)*price.Output) / 1e6
+}
+
+// Usage returns the usage recorded so far, per backend in order of first use.
+func (m *Meter) Usage() []Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Usage(nil), m.usage...)
+}
@@ -0,0 +1,61 @@
+package summarizer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMeterAddsUpUsagePerBackend(t *testing.T) {
+	m := NewMeter(map[string]Price{
+		"claude-sonnet-4-20250514": {Input: 3, Output: 15},
+		"openai/gpt-4o-mini":       {Input: 0.15, Output: 0.6},
+	})
+	ctx := WithMeter(context.Background(), m)
+
+	RecordUsage(ctx, "anthropic/claude-sonnet-4-20250514", "claude-sonnet-4-20250514", 10000, 1000)
+	RecordUsage(ctx, "openai/gpt-4o-mini", "gpt-4o-mini", 1000000, 0)
+	RecordUsage(ctx, "anthropic/claude-sonnet-4-20250514", "claude-sonnet-4-20250514", 2000, 500)
+	RecordUsage(ctx, "ollama/llama3.2", "llama3.2", 800, 200)
+	RecordUsage(context.Background(), "openai/gpt-4o-mini", "gpt-4o-mini", 5, 5) // No meter
+
+	usage := m.Usage()
+	if len(usage) != 3 {
+		t.Fatalf("Expected usage of 3 backends, got %+v", usage)
+	}
+	sonnet := usage[0]
+	if sonnet.Calls != 2 || sonnet.InputTokens != 12000 || sonnet.OutputTokens != 1500 || sonnet.CostUSD != (12000*3+1500*15)/1e6 {
+		t.Errorf("Unexpected usage priced by model name: %+v", sonnet)
+	}
+	if usage[1].CostUSD != 0.15 {
+		t.Errorf("Expected usage priced by backend, got %+v", usage[1])
+	}
+	if usage[2].Backend != "ollama/llama3.2" || usage[2].CostUSD != 0 {
+		t.Errorf("Expected an unpriced backend to cost nothing, got %+v", usage[2])
+	}
+	if total := TotalCost(usage); total != sonnet.CostUSD+0.15 {
+		t.Errorf("Unexpected total cost %v", total)
+	}
+}
+
+func TestAnthropicRecordsUsage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"content": [{"type": "text", "text": "ok"}], "usage": {"input_tokens": 1200, "output_tokens": 80}}`))
+	}))
+	defer ts.Close()
+
+	s := &AnthropicSummarizer{
+		model:  "test-model",
+		client: &http.Client{Transport: &rewriteTransport{testURL: ts.URL}},
+	}
+	m := NewMeter(nil)
+	if _, err := s.Complete(WithMeter(context.Background(), m), "prompt", 100); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+
+	usage := m.Usage()
+	if len(usage) != 1 || usage[0].Backend != "anthropic/test-model" || usage[0].InputTokens != 1200 || usage[0].OutputTokens != 80 {
+		t.Errorf("Expected the response usage to be recorded, got %+v", usage)
+	}
+}